@@ -0,0 +1,218 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import "sort"
+
+// UnionAll returns a new set with all elements of all the given sets.
+//
+// The returned set uses the same implementation as the first set. The
+// read locks of thread-safe sets are held for the whole operation and are
+// taken in a fixed order, so concurrent calls cannot deadlock. Calling
+// UnionAll without any set returns an empty thread-safe set.
+func UnionAll[T comparable](sets ...ReadOnlySet[T]) Set[T] {
+	if len(sets) == 0 {
+		return NewSet[T]()
+	}
+
+	views, unlock := rlockAll(sets)
+	defer unlock()
+
+	n := 0
+	for _, v := range views {
+		if c := v.Cardinality(); c > n {
+			n = c
+		}
+	}
+
+	ret := newSetLike[T](sets[0], n)
+	dst := unshared(ret)
+	for _, v := range views {
+		v.Each(func(elem T) bool {
+			dst.Add(elem)
+			return false
+		})
+	}
+	return ret
+}
+
+// IntersectAll returns a new set containing only the elements that exist
+// in every one of the given sets.
+//
+// The sets are visited from the smallest to the largest, so the work done
+// is bounded by the cardinality of the smallest set. The returned set uses
+// the same implementation as the first set, and locking follows the same
+// rules as UnionAll. Calling IntersectAll without any set returns an empty
+// thread-safe set.
+func IntersectAll[T comparable](sets ...ReadOnlySet[T]) Set[T] {
+	if len(sets) == 0 {
+		return NewSet[T]()
+	}
+
+	views, unlock := rlockAll(sets)
+	defer unlock()
+
+	bySize := sortByCardinality(views)
+	ret := newSetLike[T](sets[0], bySize[0].Cardinality())
+	dst := unshared(ret)
+	bySize[0].Each(func(elem T) bool {
+		for _, v := range bySize[1:] {
+			if !v.ContainsOne(elem) {
+				return false
+			}
+		}
+		dst.Add(elem)
+		return false
+	})
+	return ret
+}
+
+// DifferenceAll returns a new set containing all elements of s that are
+// not elements of any of the other sets.
+//
+// The other sets are checked from the largest to the smallest, as the
+// larger sets are the most likely to rule an element out. The returned set
+// uses the same implementation as s, and locking follows the same rules as
+// UnionAll.
+func DifferenceAll[T comparable](s ReadOnlySet[T], others ...ReadOnlySet[T]) Set[T] {
+	views, unlock := rlockAll(append([]ReadOnlySet[T]{s}, others...))
+	defer unlock()
+
+	bySize := sortByCardinality(views[1:])
+	ret := newSetLike[T](s, views[0].Cardinality())
+	dst := unshared(ret)
+	views[0].Each(func(elem T) bool {
+		for i := len(bySize) - 1; i >= 0; i-- {
+			if bySize[i].ContainsOne(elem) {
+				return false
+			}
+		}
+		dst.Add(elem)
+		return false
+	})
+	return ret
+}
+
+// sortByCardinality returns a copy of collections ordered from the smallest
+// to the largest.
+func sortByCardinality[T comparable](collections []Collection[T]) []Collection[T] {
+	sizes := make([]int, len(collections))
+	sorted := make([]Collection[T], len(collections))
+	for i, c := range collections {
+		sizes[i] = c.Cardinality()
+		sorted[i] = c
+	}
+	sort.Sort(byCardinality[T]{sorted, sizes})
+	return sorted
+}
+
+type byCardinality[T comparable] struct {
+	collections []Collection[T]
+	sizes       []int
+}
+
+func (b byCardinality[T]) Len() int           { return len(b.collections) }
+func (b byCardinality[T]) Less(i, j int) bool { return b.sizes[i] < b.sizes[j] }
+func (b byCardinality[T]) Swap(i, j int) {
+	b.collections[i], b.collections[j] = b.collections[j], b.collections[i]
+	b.sizes[i], b.sizes[j] = b.sizes[j], b.sizes[i]
+}
+
+// UnionUpdate adds all elements of other to s in place and returns the
+// number of elements added.
+//
+// Sets implementing Updater, such as every set of this package, are updated
+// by their UnionUpdate method; a thread-safe set of this package is updated
+// under a single write lock. Other sets are updated through their Add
+// method, one element at a time.
+func UnionUpdate[T comparable](s Set[T], other Set[T]) int {
+	if u, ok := s.(Updater[T]); ok {
+		return u.UnionUpdate(other)
+	}
+	n := 0
+	for _, elem := range other.ToSlice() {
+		if s.Add(elem) {
+			n++
+		}
+	}
+	return n
+}
+
+// IntersectUpdate removes all elements of s that are not also elements of
+// other and returns the number of elements removed. Sets implementing
+// Updater are updated as by UnionUpdate, other sets through their Remove
+// method.
+func IntersectUpdate[T comparable](s Set[T], other Set[T]) int {
+	if u, ok := s.(Updater[T]); ok {
+		return u.IntersectUpdate(other)
+	}
+	var drop []T
+	s.Each(func(elem T) bool {
+		if !other.ContainsOne(elem) {
+			drop = append(drop, elem)
+		}
+		return false
+	})
+	s.RemoveAll(drop...)
+	return len(drop)
+}
+
+// DifferenceUpdate removes all elements of other from s and returns the
+// number of elements removed. Sets implementing Updater are updated as by
+// UnionUpdate, other sets through their Remove method.
+func DifferenceUpdate[T comparable](s Set[T], other Set[T]) int {
+	if u, ok := s.(Updater[T]); ok {
+		return u.DifferenceUpdate(other)
+	}
+	var drop []T
+	for _, elem := range other.ToSlice() {
+		if s.ContainsOne(elem) {
+			drop = append(drop, elem)
+		}
+	}
+	s.RemoveAll(drop...)
+	return len(drop)
+}
+
+// SymmetricDifferenceUpdate updates s in place to hold the elements which
+// are in either s or other but not in both, and returns the number of
+// elements added and removed. Sets implementing Updater are updated as by
+// UnionUpdate, other sets through their Add and Remove methods.
+func SymmetricDifferenceUpdate[T comparable](s Set[T], other Set[T]) (added, removed int) {
+	if u, ok := s.(Updater[T]); ok {
+		return u.SymmetricDifferenceUpdate(other)
+	}
+	for _, elem := range other.ToSlice() {
+		if s.ContainsOne(elem) {
+			s.Remove(elem)
+			removed++
+		} else {
+			s.Add(elem)
+			added++
+		}
+	}
+	return added, removed
+}
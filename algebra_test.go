@@ -0,0 +1,125 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"testing"
+)
+
+func Test_UnionAll(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		a := ctor(1, 2)
+		union := UnionAll[int](a, NewSet(2, 3), NewThreadUnsafeSet(4), foreignSet[int]{NewSet(5)}, a)
+
+		assertEqual(union, NewSet(1, 2, 3, 4, 5), t)
+		if !a.Equal(NewSet(1, 2)) {
+			t.Error("UnionAll should not modify its arguments")
+		}
+		if _, ok := UnionAll[int](ctor(), ctor()).(*threadUnsafeSet[int]); ok != isThreadUnsafe(a) {
+			t.Error("UnionAll should return a set of the same implementation as the first set")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+
+	if !UnionAll[int]().IsEmpty() {
+		t.Error("UnionAll without any set should return the empty set")
+	}
+}
+
+func Test_IntersectAll(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		a := ctor(1, 2, 3, 4, 5)
+		intersection := IntersectAll[int](a, NewSet(2, 3, 4, 9), NewThreadUnsafeSet(3, 4), foreignSet[int]{NewSet(4, 3, 0)}, a)
+
+		assertEqual(intersection, NewSet(3, 4), t)
+		if !IntersectAll[int](a, NewSet[int]()).IsEmpty() {
+			t.Error("The intersection with the empty set should be empty")
+		}
+		assertEqual(IntersectAll[int](a), a, t)
+		if _, ok := IntersectAll[int](ctor(), NewThreadUnsafeSet[int]()).(*threadUnsafeSet[int]); ok != isThreadUnsafe(a) {
+			t.Error("IntersectAll should return a set of the same implementation as the first set")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+
+	if !IntersectAll[int]().IsEmpty() {
+		t.Error("IntersectAll without any set should return the empty set")
+	}
+}
+
+func Test_DifferenceAll(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		a := ctor(1, 2, 3, 4, 5)
+		diff := DifferenceAll[int](a, NewSet(2, 9), NewThreadUnsafeSet(3), foreignSet[int]{NewSet(4)})
+
+		assertEqual(diff, NewSet(1, 5), t)
+		assertEqual(DifferenceAll[int](a), a, t)
+		if !DifferenceAll[int](a, NewSet(1), a).IsEmpty() {
+			t.Error("The difference of a set with itself should be empty")
+		}
+		if _, ok := DifferenceAll[int](ctor(), NewThreadUnsafeSet[int]()).(*threadUnsafeSet[int]); ok != isThreadUnsafe(a) {
+			t.Error("DifferenceAll should return a set of the same implementation as the first set")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_AllReadOnly(t *testing.T) {
+	a := Freeze(NewThreadUnsafeSet(1, 2, 3))
+	b := Freeze(NewSet(2, 3, 4))
+
+	u := UnionAll(a, b)
+	assertEqual(u, NewSet(1, 2, 3, 4), t)
+	if _, ok := u.(*threadSafeSet[int]); !ok {
+		t.Errorf("UnionAll of frozen sets should return a thread-safe set, got %T", u)
+	}
+	assertEqual(IntersectAll(a, b), NewSet(2, 3), t)
+	assertEqual(DifferenceAll(a, b), NewSet(1), t)
+	assertEqual(DifferenceAll[int](NewThreadUnsafeSet(1, 2, 5), a), NewThreadUnsafeSet(5), t)
+}
+
+func isThreadUnsafe[T comparable](s Set[T]) bool {
+	_, ok := s.(*threadUnsafeSet[T])
+	return ok
+}
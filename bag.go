@@ -0,0 +1,526 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Bag is a multiset: like a Set, but it counts how many times each element
+// has been added. It is useful for tallying occurrences while still being
+// able to combine the tallies with set algebra.
+//
+// The binary operations accept any Bag implementation and return a bag of
+// the same implementation as the receiver.
+type Bag[T comparable] interface {
+	// Add adds one occurrence of v and returns its new count.
+	Add(v T) int
+
+	// AddN adds n occurrences of v and returns its new count. It does
+	// nothing when n is not positive.
+	AddN(v T, n int) int
+
+	// Append adds one occurrence of each of the given values.
+	Append(v ...T)
+
+	// Clear removes all elements from the bag, leaving
+	// the empty bag.
+	Clear()
+
+	// Clone returns a clone of the bag using the same
+	// implementation, duplicating all counts.
+	Clone() Bag[T]
+
+	// Count returns the number of occurrences of v.
+	Count(v T) int
+
+	// Difference returns a bag where each count is the count in this bag
+	// minus the count in other, dropping elements that reach zero.
+	Difference(other Bag[T]) Bag[T]
+
+	// Distinct returns the set of elements that occur at least once. The
+	// set is thread-safe when the bag is.
+	Distinct() Set[T]
+
+	// Each iterates over the distinct elements with their counts and
+	// executes the passed func against each of them. If passed func
+	// returns true, stop iteration at the time.
+	Each(func(T, int) bool)
+
+	// Equal determines if two bags hold the same elements with the same
+	// counts.
+	Equal(other Bag[T]) bool
+
+	// Intersect returns a bag where each count is the smaller of the
+	// counts in this bag and other.
+	Intersect(other Bag[T]) Bag[T]
+
+	// IsEmpty determines if there are elements in the bag.
+	IsEmpty() bool
+
+	// Len returns the total number of occurrences of all elements.
+	Len() int
+
+	// Remove removes every occurrence of v and returns how many
+	// there were.
+	Remove(v T) int
+
+	// RemoveOne removes one occurrence of v and reports whether v
+	// was present.
+	RemoveOne(v T) bool
+
+	// String provides a convenient string representation
+	// of the current state of the bag.
+	String() string
+
+	// Sum returns a bag where each count is the sum of the counts in
+	// this bag and other.
+	Sum(other Bag[T]) Bag[T]
+
+	// ToMap returns the counts of the bag as a map.
+	ToMap() map[T]int
+
+	// Union returns a bag where each count is the larger of the counts
+	// in this bag and other.
+	Union(other Bag[T]) Bag[T]
+
+	// MarshalJSON will marshal the bag into a JSON object mapping each
+	// element to its count. Elements must be usable as JSON object keys:
+	// strings, integers or encoding.TextMarshaler implementations.
+	MarshalJSON() ([]byte, error)
+
+	// UnmarshalJSON will add the counts of a JSON object to the bag.
+	UnmarshalJSON(b []byte) error
+}
+
+// NewBag creates and returns a new bag with one occurrence of each of the
+// given elements. Operations on the resulting bag are thread-safe.
+func NewBag[T comparable](vals ...T) Bag[T] {
+	b := &threadSafeBag[T]{ub: newThreadUnsafeBag[T]()}
+	b.ub.Append(vals...)
+	return b
+}
+
+// NewThreadUnsafeBag creates and returns a new bag with one occurrence of
+// each of the given elements. Operations on the resulting bag are not
+// thread-safe.
+func NewThreadUnsafeBag[T comparable](vals ...T) Bag[T] {
+	b := newThreadUnsafeBag[T]()
+	b.Append(vals...)
+	return b
+}
+
+func newThreadUnsafeBag[T comparable]() *threadUnsafeBag[T] {
+	return &threadUnsafeBag[T]{}
+}
+
+type threadUnsafeBag[T comparable] map[T]int
+
+// Assert concrete types:threadUnsafeBag and threadSafeBag adhere to Bag interface.
+var (
+	_ Bag[string] = (*threadUnsafeBag[string])(nil)
+	_ Bag[string] = (*threadSafeBag[string])(nil)
+)
+
+// Assert concrete type:threadSafeBag takes part in multi-bag locking.
+var _ rwLocked[Bag[string]] = (*threadSafeBag[string])(nil)
+
+func (b *threadUnsafeBag[T]) Add(v T) int {
+	(*b)[v]++
+	return (*b)[v]
+}
+
+func (b *threadUnsafeBag[T]) AddN(v T, n int) int {
+	if n > 0 {
+		(*b)[v] += n
+	}
+	return (*b)[v]
+}
+
+func (b *threadUnsafeBag[T]) Append(v ...T) {
+	for _, val := range v {
+		(*b)[val]++
+	}
+}
+
+func (b *threadUnsafeBag[T]) Clear() {
+	for elem := range *b {
+		delete(*b, elem)
+	}
+}
+
+func (b *threadUnsafeBag[T]) Clone() Bag[T] {
+	return b.clone()
+}
+
+func (b *threadUnsafeBag[T]) clone() *threadUnsafeBag[T] {
+	c := make(threadUnsafeBag[T], len(*b))
+	for elem, n := range *b {
+		c[elem] = n
+	}
+	return &c
+}
+
+func (b *threadUnsafeBag[T]) Count(v T) int {
+	return (*b)[v]
+}
+
+func (b *threadUnsafeBag[T]) Difference(other Bag[T]) Bag[T] {
+	o, unlock := rlockOther(other)
+	defer unlock()
+
+	return b.difference(o)
+}
+
+func (b *threadUnsafeBag[T]) difference(o Bag[T]) *threadUnsafeBag[T] {
+	diff := newThreadUnsafeBag[T]()
+	for elem, n := range *b {
+		if n -= o.Count(elem); n > 0 {
+			(*diff)[elem] = n
+		}
+	}
+	return diff
+}
+
+func (b *threadUnsafeBag[T]) Distinct() Set[T] {
+	return b.distinct()
+}
+
+func (b *threadUnsafeBag[T]) distinct() *threadUnsafeSet[T] {
+	s := newThreadUnsafeSetWithSize[T](len(*b))
+	for elem := range *b {
+		s.Add(elem)
+	}
+	return s
+}
+
+func (b *threadUnsafeBag[T]) Each(cb func(T, int) bool) {
+	for elem, n := range *b {
+		if cb(elem, n) {
+			break
+		}
+	}
+}
+
+func (b *threadUnsafeBag[T]) Equal(other Bag[T]) bool {
+	o, unlock := rlockOther(other)
+	defer unlock()
+
+	return b.equal(o)
+}
+
+func (b *threadUnsafeBag[T]) equal(o Bag[T]) bool {
+	distinct, equal := 0, true
+	o.Each(func(elem T, n int) bool {
+		distinct++
+		equal = (*b)[elem] == n
+		return !equal
+	})
+	return equal && distinct == len(*b)
+}
+
+func (b *threadUnsafeBag[T]) Intersect(other Bag[T]) Bag[T] {
+	o, unlock := rlockOther(other)
+	defer unlock()
+
+	return b.intersect(o)
+}
+
+func (b *threadUnsafeBag[T]) intersect(o Bag[T]) *threadUnsafeBag[T] {
+	intersection := newThreadUnsafeBag[T]()
+	for elem, n := range *b {
+		if m := o.Count(elem); m < n {
+			n = m
+		}
+		if n > 0 {
+			(*intersection)[elem] = n
+		}
+	}
+	return intersection
+}
+
+func (b *threadUnsafeBag[T]) IsEmpty() bool {
+	return len(*b) == 0
+}
+
+func (b *threadUnsafeBag[T]) Len() int {
+	total := 0
+	for _, n := range *b {
+		total += n
+	}
+	return total
+}
+
+func (b *threadUnsafeBag[T]) Remove(v T) int {
+	n := (*b)[v]
+	delete(*b, v)
+	return n
+}
+
+func (b *threadUnsafeBag[T]) RemoveOne(v T) bool {
+	n, ok := (*b)[v]
+	if !ok {
+		return false
+	}
+	if n == 1 {
+		delete(*b, v)
+	} else {
+		(*b)[v] = n - 1
+	}
+	return true
+}
+
+func (b *threadUnsafeBag[T]) String() string {
+	items := make([]string, 0, len(*b))
+
+	for elem, n := range *b {
+		items = append(items, fmt.Sprintf("%v: %d", elem, n))
+	}
+	return fmt.Sprintf("Bag{%s}", strings.Join(items, ", "))
+}
+
+func (b *threadUnsafeBag[T]) Sum(other Bag[T]) Bag[T] {
+	o, unlock := rlockOther(other)
+	defer unlock()
+
+	return b.sum(o)
+}
+
+func (b *threadUnsafeBag[T]) sum(o Bag[T]) *threadUnsafeBag[T] {
+	sum := b.clone()
+	o.Each(func(elem T, n int) bool {
+		(*sum)[elem] += n
+		return false
+	})
+	return sum
+}
+
+func (b *threadUnsafeBag[T]) ToMap() map[T]int {
+	return *b.clone()
+}
+
+func (b *threadUnsafeBag[T]) Union(other Bag[T]) Bag[T] {
+	o, unlock := rlockOther(other)
+	defer unlock()
+
+	return b.union(o)
+}
+
+func (b *threadUnsafeBag[T]) union(o Bag[T]) *threadUnsafeBag[T] {
+	union := b.clone()
+	o.Each(func(elem T, n int) bool {
+		if n > (*union)[elem] {
+			(*union)[elem] = n
+		}
+		return false
+	})
+	return union
+}
+
+// MarshalJSON creates a JSON object mapping each element to its count.
+func (b *threadUnsafeBag[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[T]int(*b))
+}
+
+// UnmarshalJSON adds the counts of a JSON object to the bag. Negative
+// counts are rejected.
+func (b *threadUnsafeBag[T]) UnmarshalJSON(p []byte) error {
+	var counts map[T]int
+	if err := json.Unmarshal(p, &counts); err != nil {
+		return err
+	}
+	for elem, n := range counts {
+		if n < 0 {
+			return fmt.Errorf("mapset: negative count %d for bag element %v", n, elem)
+		}
+	}
+	for elem, n := range counts {
+		b.AddN(elem, n)
+	}
+	return nil
+}
+
+type threadSafeBag[T comparable] struct {
+	sync.RWMutex
+	ub *threadUnsafeBag[T]
+}
+
+func (t *threadSafeBag[T]) rwMutex() *sync.RWMutex {
+	return &t.RWMutex
+}
+
+func (t *threadSafeBag[T]) unlocked() Bag[T] {
+	return t.ub
+}
+
+func (t *threadSafeBag[T]) Add(v T) int {
+	t.Lock()
+	ret := t.ub.Add(v)
+	t.Unlock()
+	return ret
+}
+
+func (t *threadSafeBag[T]) AddN(v T, n int) int {
+	t.Lock()
+	ret := t.ub.AddN(v, n)
+	t.Unlock()
+	return ret
+}
+
+func (t *threadSafeBag[T]) Append(v ...T) {
+	t.Lock()
+	t.ub.Append(v...)
+	t.Unlock()
+}
+
+func (t *threadSafeBag[T]) Clear() {
+	t.Lock()
+	t.ub.Clear()
+	t.Unlock()
+}
+
+func (t *threadSafeBag[T]) Clone() Bag[T] {
+	t.RLock()
+	ret := &threadSafeBag[T]{ub: t.ub.clone()}
+	t.RUnlock()
+	return ret
+}
+
+func (t *threadSafeBag[T]) Count(v T) int {
+	t.RLock()
+	ret := t.ub.Count(v)
+	t.RUnlock()
+	return ret
+}
+
+func (t *threadSafeBag[T]) Difference(other Bag[T]) Bag[T] {
+	o, unlock := rlockPair[Bag[T]](t, other)
+	ret := &threadSafeBag[T]{ub: t.ub.difference(o)}
+	unlock()
+	return ret
+}
+
+func (t *threadSafeBag[T]) Distinct() Set[T] {
+	t.RLock()
+	ret := &threadSafeSet[T]{uss: t.ub.distinct()}
+	t.RUnlock()
+	return ret
+}
+
+func (t *threadSafeBag[T]) Each(cb func(T, int) bool) {
+	t.RLock()
+	defer t.RUnlock()
+	t.ub.Each(cb)
+}
+
+func (t *threadSafeBag[T]) Equal(other Bag[T]) bool {
+	o, unlock := rlockPair[Bag[T]](t, other)
+	ret := t.ub.equal(o)
+	unlock()
+	return ret
+}
+
+func (t *threadSafeBag[T]) Intersect(other Bag[T]) Bag[T] {
+	o, unlock := rlockPair[Bag[T]](t, other)
+	ret := &threadSafeBag[T]{ub: t.ub.intersect(o)}
+	unlock()
+	return ret
+}
+
+func (t *threadSafeBag[T]) IsEmpty() bool {
+	t.RLock()
+	ret := t.ub.IsEmpty()
+	t.RUnlock()
+	return ret
+}
+
+func (t *threadSafeBag[T]) Len() int {
+	t.RLock()
+	ret := t.ub.Len()
+	t.RUnlock()
+	return ret
+}
+
+func (t *threadSafeBag[T]) Remove(v T) int {
+	t.Lock()
+	ret := t.ub.Remove(v)
+	t.Unlock()
+	return ret
+}
+
+func (t *threadSafeBag[T]) RemoveOne(v T) bool {
+	t.Lock()
+	ret := t.ub.RemoveOne(v)
+	t.Unlock()
+	return ret
+}
+
+func (t *threadSafeBag[T]) String() string {
+	t.RLock()
+	ret := t.ub.String()
+	t.RUnlock()
+	return ret
+}
+
+func (t *threadSafeBag[T]) Sum(other Bag[T]) Bag[T] {
+	o, unlock := rlockPair[Bag[T]](t, other)
+	ret := &threadSafeBag[T]{ub: t.ub.sum(o)}
+	unlock()
+	return ret
+}
+
+func (t *threadSafeBag[T]) ToMap() map[T]int {
+	t.RLock()
+	ret := t.ub.ToMap()
+	t.RUnlock()
+	return ret
+}
+
+func (t *threadSafeBag[T]) Union(other Bag[T]) Bag[T] {
+	o, unlock := rlockPair[Bag[T]](t, other)
+	ret := &threadSafeBag[T]{ub: t.ub.union(o)}
+	unlock()
+	return ret
+}
+
+func (t *threadSafeBag[T]) MarshalJSON() ([]byte, error) {
+	t.RLock()
+	b, err := t.ub.MarshalJSON()
+	t.RUnlock()
+
+	return b, err
+}
+
+func (t *threadSafeBag[T]) UnmarshalJSON(p []byte) error {
+	t.Lock()
+	err := t.ub.UnmarshalJSON(p)
+	t.Unlock()
+
+	return err
+}
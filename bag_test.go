@@ -0,0 +1,190 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func Test_BagCounts(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...string) Bag[string]) {
+		b := ctor("a", "b", "a")
+		if n := b.Add("a"); n != 3 {
+			t.Errorf("Add should return the new count 3, got %d", n)
+		}
+		if n := b.AddN("c", 4); n != 4 {
+			t.Errorf("AddN should return the new count 4, got %d", n)
+		}
+		if n := b.AddN("c", -1); n != 4 {
+			t.Errorf("AddN with a negative count should do nothing, got %d", n)
+		}
+		if b.Count("a") != 3 || b.Count("b") != 1 || b.Count("z") != 0 {
+			t.Errorf("Unexpected counts: %v", b.ToMap())
+		}
+		if b.Len() != 8 {
+			t.Errorf("Len should count every occurrence, got %d", b.Len())
+		}
+		assertEqual(b.Distinct(), NewSet("a", "b", "c"), t)
+
+		if !b.RemoveOne("b") || b.RemoveOne("b") || b.Count("b") != 0 {
+			t.Error("RemoveOne should drop the last occurrence and report absent elements")
+		}
+		if n := b.Remove("c"); n != 4 {
+			t.Errorf("Remove should report the 4 removed occurrences, got %d", n)
+		}
+		if !reflect.DeepEqual(b.ToMap(), map[string]int{"a": 3}) {
+			t.Errorf("Unexpected counts: %v", b.ToMap())
+		}
+		if got := b.String(); got != "Bag{a: 3}" {
+			t.Errorf("Unexpected String: %s", got)
+		}
+
+		c := b.Clone()
+		c.Add("a")
+		if b.Count("a") != 3 {
+			t.Error("Modifying a clone should not modify the original")
+		}
+		b.Clear()
+		if !b.IsEmpty() || b.Len() != 0 {
+			t.Error("Clear should empty the bag")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) { test(t, NewBag[string]) })
+	t.Run("Unsafe", func(t *testing.T) { test(t, NewThreadUnsafeBag[string]) })
+}
+
+func Test_BagAlgebra(t *testing.T) {
+	counts := func(b Bag[string]) map[string]int { return b.ToMap() }
+
+	for _, ctors := range []struct {
+		name string
+		a, b func(vals ...string) Bag[string]
+	}{
+		{"Safe", NewBag[string], NewBag[string]},
+		{"Unsafe", NewThreadUnsafeBag[string], NewThreadUnsafeBag[string]},
+		{"Mixed", NewBag[string], NewThreadUnsafeBag[string]},
+	} {
+		t.Run(ctors.name, func(t *testing.T) {
+			a := ctors.a("x", "x", "x", "y", "z")
+			b := ctors.b("x", "y", "y", "w")
+
+			if got := counts(a.Union(b)); !reflect.DeepEqual(got, map[string]int{"x": 3, "y": 2, "z": 1, "w": 1}) {
+				t.Errorf("Union should keep the larger counts, got %v", got)
+			}
+			if got := counts(a.Intersect(b)); !reflect.DeepEqual(got, map[string]int{"x": 1, "y": 1}) {
+				t.Errorf("Intersect should keep the smaller counts, got %v", got)
+			}
+			if got := counts(a.Sum(b)); !reflect.DeepEqual(got, map[string]int{"x": 4, "y": 3, "z": 1, "w": 1}) {
+				t.Errorf("Sum should add the counts, got %v", got)
+			}
+			if got := counts(a.Difference(b)); !reflect.DeepEqual(got, map[string]int{"x": 2, "z": 1}) {
+				t.Errorf("Difference should subtract the counts, got %v", got)
+			}
+			if got := a.Difference(a); !got.IsEmpty() {
+				t.Errorf("A bag minus itself should be empty, got %v", got)
+			}
+			if reflect.TypeOf(a.Union(b)) != reflect.TypeOf(a) {
+				t.Error("Results should use the receiver's implementation")
+			}
+
+			if a.Equal(b) || !a.Equal(ctors.b("z", "x", "y", "x", "x")) || a.Equal(ctors.b("x", "y", "z")) {
+				t.Error("Equal should compare every count")
+			}
+		})
+	}
+}
+
+func Test_BagJSON(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...string) Bag[string]) {
+		b := ctor("a", "b", "a")
+		data, err := json.Marshal(b)
+		if err != nil {
+			t.Errorf("Error should be nil: %v", err)
+		}
+		if string(data) != `{"a":2,"b":1}` {
+			t.Errorf("Expected {\"a\":2,\"b\":1}, got %s", data)
+		}
+
+		u := ctor("a")
+		if err := json.Unmarshal(data, u); err != nil {
+			t.Errorf("Error should be nil: %v", err)
+		}
+		if !reflect.DeepEqual(u.ToMap(), map[string]int{"a": 3, "b": 1}) {
+			t.Errorf("UnmarshalJSON should add the decoded counts, got %v", u.ToMap())
+		}
+		if err := json.Unmarshal([]byte(`{"c":-1}`), u); err == nil {
+			t.Error("Expected an error decoding a negative count")
+		}
+		if u.Count("c") != 0 {
+			t.Error("A rejected object should not change the bag")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) { test(t, NewBag[string]) })
+	t.Run("Unsafe", func(t *testing.T) { test(t, NewThreadUnsafeBag[string]) })
+
+	ints := NewBag(3, 1, 3)
+	data, err := json.Marshal(ints)
+	if err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+	if string(data) != `{"1":1,"3":2}` {
+		t.Errorf("Integer elements should be encoded as object keys, got %s", data)
+	}
+}
+
+func Test_BagConcurrent(t *testing.T) {
+	a, b := NewBag[int](), NewBag[int]()
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(4)
+		go func(i int) {
+			defer wg.Done()
+			a.Add(i % 10)
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			b.AddN(i%7, 2)
+		}(i)
+		go func() {
+			defer wg.Done()
+			a.Union(b)
+		}()
+		go func() {
+			defer wg.Done()
+			b.Sum(a)
+		}()
+	}
+	wg.Wait()
+
+	if a.Len() != 100 || b.Len() != 200 {
+		t.Errorf("Expected 100 and 200 occurrences, got %d and %d", a.Len(), b.Len())
+	}
+}
@@ -57,6 +57,24 @@ func BenchmarkAddUnsafe(b *testing.B) {
 	benchAdd(b, 1000, NewThreadUnsafeSet[int])
 }
 
+func benchAddParallel(b *testing.B, s Set[int]) {
+	nums := nrand(1000)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			s.Add(nums[i%len(nums)])
+		}
+	})
+}
+
+func BenchmarkAddParallelSafe(b *testing.B) {
+	benchAddParallel(b, NewSet[int]())
+}
+
+func BenchmarkAddParallelSharded(b *testing.B) {
+	benchAddParallel(b, NewShardedSet[int](0, nil))
+}
+
 func benchRemove(b *testing.B, s Set[int]) {
 	nums := nrand(b.N)
 	for _, v := range nums {
@@ -216,6 +234,18 @@ func BenchmarkContainsOne100Unsafe(b *testing.B) {
 	benchContainsOne(b, 100, NewThreadUnsafeSet[int]())
 }
 
+func BenchmarkContainsOne100Perfect(b *testing.B) {
+	p, err := NewPerfectSet(NewSet(nrand(100)...))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.ContainsOne(-1)
+	}
+}
+
 // In this scenario, Contains argument escapes to the heap, while ContainsOne does not.
 func benchContainsComparison(b *testing.B, n int, s Set[int]) {
 	nums := nrand(n)
@@ -536,6 +566,10 @@ func BenchmarkIntersect100Unsafe(b *testing.B) {
 	benchIntersect(b, 100, NewThreadUnsafeSet[int](), NewThreadUnsafeSet[int]())
 }
 
+func BenchmarkIntersect100Roaring(b *testing.B) {
+	benchIntersect(b, 100, NewRoaringSet[int](), NewRoaringSet[int]())
+}
+
 func benchSymmetricDifference(b *testing.B, n int, s, t Set[int]) {
 	nums := nrand(int(float64(n) * float64(1.5)))
 	for _, v := range nums[:n] {
@@ -614,6 +648,73 @@ func BenchmarkUnion100Unsafe(b *testing.B) {
 	benchUnion(b, 100, NewThreadUnsafeSet[int](), NewThreadUnsafeSet[int]())
 }
 
+func BenchmarkUnion100Roaring(b *testing.B) {
+	benchUnion(b, 100, NewRoaringSet[int](), NewRoaringSet[int]())
+}
+
+func benchUnionUpdate(b *testing.B, n int, s, t Set[int]) {
+	nums := nrand(n)
+	for _, v := range nums[:n/2] {
+		s.Add(v)
+	}
+	for _, v := range nums[n/2:] {
+		t.Add(v)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		UnionUpdate(s, t)
+	}
+}
+
+func BenchmarkUnionUpdate1Safe(b *testing.B) {
+	benchUnionUpdate(b, 1, NewSet[int](), NewSet[int]())
+}
+
+func BenchmarkUnionUpdate1Unsafe(b *testing.B) {
+	benchUnionUpdate(b, 1, NewThreadUnsafeSet[int](), NewThreadUnsafeSet[int]())
+}
+
+func BenchmarkUnionUpdate10Safe(b *testing.B) {
+	benchUnionUpdate(b, 10, NewSet[int](), NewSet[int]())
+}
+
+func BenchmarkUnionUpdate10Unsafe(b *testing.B) {
+	benchUnionUpdate(b, 10, NewThreadUnsafeSet[int](), NewThreadUnsafeSet[int]())
+}
+
+func BenchmarkUnionUpdate100Safe(b *testing.B) {
+	benchUnionUpdate(b, 100, NewSet[int](), NewSet[int]())
+}
+
+func BenchmarkUnionUpdate100Unsafe(b *testing.B) {
+	benchUnionUpdate(b, 100, NewThreadUnsafeSet[int](), NewThreadUnsafeSet[int]())
+}
+
+func benchIntersectAll(b *testing.B, n int, newSet func(...int) Set[int]) {
+	sets := make([]ReadOnlySet[int], 20)
+	for i := range sets {
+		s := newSet()
+		for _, v := range nrand(n) {
+			s.Add(v % (2 * n))
+		}
+		sets[i] = s
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		IntersectAll(sets...)
+	}
+}
+
+func BenchmarkIntersectAll100Safe(b *testing.B) {
+	benchIntersectAll(b, 100, NewSet[int])
+}
+
+func BenchmarkIntersectAll100Unsafe(b *testing.B) {
+	benchIntersectAll(b, 100, NewThreadUnsafeSet[int])
+}
+
 func benchEach(b *testing.B, n int, s Set[int]) {
 	nums := nrand(n)
 	for _, v := range nums {
@@ -0,0 +1,297 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"reflect"
+	"sort"
+	"unsafe"
+)
+
+// The binary form of a Set is, with fixed-size integers little-endian:
+//
+//	magic      4 bytes, "MSET"
+//	version    1 byte, currently 1
+//	kind       1 byte: 1 for signed integers, 2 for unsigned integers,
+//	           3 for strings, plus 0x80 for elements in insertion order
+//	size       1 byte, the size in bytes of the integers, 0 for strings
+//	count      uvarint, the number of elements
+//	elements   integers in ascending order, the first as a varint (zigzag
+//	           for signed integers) and each following one as the uvarint
+//	           difference with its predecessor; strings in ascending order,
+//	           each as a uvarint length followed by its bytes
+//	checksum   uint32, the CRC-32 (IEEE) of everything before it
+//
+// Ordered sets write their elements in insertion order instead, each
+// integer after the first as the varint (zigzag) wrapping difference with
+// its predecessor. Integer elements may be decoded into any integer type
+// able to hold every one of them.
+const (
+	binaryMagic   = "MSET"
+	binaryVersion = 1
+
+	binarySigned   = 1
+	binaryUnsigned = 2
+	binaryString   = 3
+
+	binaryOrdered = 0x80
+)
+
+var errBinaryFormat = errors.New("mapset: malformed set data")
+
+// binaryKindOf returns the kind and size under which elements of type T
+// are encoded.
+func binaryKindOf[T comparable]() (kind byte, size int, err error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binarySigned, int(t.Size()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binaryUnsigned, int(t.Size()), nil
+	case reflect.String:
+		return binaryString, 0, nil
+	}
+	return 0, 0, fmt.Errorf("mapset: binary encoding does not support %s elements", t)
+}
+
+// loadBits returns the size bytes at ptr as an unsigned integer.
+func loadBits(ptr unsafe.Pointer, size int) uint64 {
+	switch size {
+	case 1:
+		return uint64(*(*uint8)(ptr))
+	case 2:
+		return uint64(*(*uint16)(ptr))
+	case 4:
+		return uint64(*(*uint32)(ptr))
+	}
+	return *(*uint64)(ptr)
+}
+
+// storeBits stores the low size bytes of x at ptr.
+func storeBits(ptr unsafe.Pointer, size int, x uint64) {
+	switch size {
+	case 1:
+		*(*uint8)(ptr) = uint8(x)
+	case 2:
+		*(*uint16)(ptr) = uint16(x)
+	case 4:
+		*(*uint32)(ptr) = uint32(x)
+	default:
+		*(*uint64)(ptr) = x
+	}
+}
+
+// signExtend interprets the low size bytes of x as a signed integer.
+func signExtend(x uint64, size int) int64 {
+	shift := 64 - 8*size
+	return int64(x<<shift) >> shift
+}
+
+// marshalCollectionBinary encodes the elements of c in the binary form
+// described above, in the iteration order of c when ordered is set.
+func marshalCollectionBinary[T comparable](c Collection[T], ordered bool) ([]byte, error) {
+	kind, size, err := binaryKindOf[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 0, 16+2*c.Cardinality())
+	b = append(b, binaryMagic...)
+	if ordered {
+		b = append(b, binaryVersion, kind|binaryOrdered, byte(size))
+	} else {
+		b = append(b, binaryVersion, kind, byte(size))
+	}
+	b = appendUvarint(b, uint64(c.Cardinality()))
+
+	if kind == binaryString {
+		vals := make([]string, 0, c.Cardinality())
+		c.Each(func(elem T) bool {
+			vals = append(vals, *(*string)(unsafe.Pointer(&elem)))
+			return false
+		})
+		if !ordered {
+			sort.Strings(vals)
+		}
+		for _, v := range vals {
+			b = appendUvarint(b, uint64(len(v)))
+			b = append(b, v...)
+		}
+	} else {
+		vals := make([]uint64, 0, c.Cardinality())
+		c.Each(func(elem T) bool {
+			x := loadBits(unsafe.Pointer(&elem), size)
+			if kind == binarySigned {
+				x = uint64(signExtend(x, size))
+			}
+			vals = append(vals, x)
+			return false
+		})
+		switch {
+		case ordered:
+		case kind == binarySigned:
+			sort.Slice(vals, func(i, j int) bool { return int64(vals[i]) < int64(vals[j]) })
+		default:
+			sort.Slice(vals, func(i, j int) bool { return vals[i] < vals[j] })
+		}
+		for i, v := range vals {
+			switch {
+			case i > 0 && ordered:
+				b = appendVarint(b, int64(v-vals[i-1]))
+			case i > 0:
+				// Differences between ascending values are positive, and
+				// wrapping subtraction gives them exactly.
+				b = appendUvarint(b, v-vals[i-1])
+			case kind == binarySigned:
+				b = appendVarint(b, int64(v))
+			default:
+				b = appendUvarint(b, v)
+			}
+		}
+	}
+
+	return appendUint32(b, crc32.ChecksumIEEE(b)), nil
+}
+
+// unmarshalBinaryElements decodes the elements of data produced by
+// marshalCollectionBinary, in the order in which they were written.
+func unmarshalBinaryElements[T comparable](data []byte) ([]T, error) {
+	kind, size, err := binaryKindOf[T]()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 12 || string(data[:4]) != binaryMagic {
+		return nil, errBinaryFormat
+	}
+	body := data[:len(data)-4]
+	if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(data[len(data)-4:]) {
+		return nil, errors.New("mapset: set data checksum mismatch")
+	}
+	if body[4] != binaryVersion {
+		return nil, fmt.Errorf("mapset: unsupported set data version %d", body[4])
+	}
+	srcKind, srcSize := body[5]&^binaryOrdered, int(body[6])
+	ordered := body[5]&binaryOrdered != 0
+	switch {
+	case (srcKind == binaryString) != (kind == binaryString):
+		return nil, errors.New("mapset: set data element kind does not match the set")
+	case srcKind != binaryString && srcKind != binarySigned && srcKind != binaryUnsigned,
+		srcKind == binaryString && srcSize != 0,
+		srcKind != binaryString && srcSize != 1 && srcSize != 2 && srcSize != 4 && srcSize != 8:
+		return nil, errBinaryFormat
+	}
+	body = body[7:]
+
+	count, n := binary.Uvarint(body)
+	if n <= 0 || count > uint64(len(body)) {
+		return nil, errBinaryFormat
+	}
+	body = body[n:]
+
+	vals := make([]T, count)
+	var prev uint64
+	for i := range vals {
+		ptr := unsafe.Pointer(&vals[i])
+		if kind == binaryString {
+			l, n := binary.Uvarint(body)
+			if n <= 0 || uint64(len(body)-n) < l {
+				return nil, errBinaryFormat
+			}
+			*(*string)(ptr) = string(body[n : n+int(l)])
+			body = body[n+int(l):]
+			continue
+		}
+
+		var x uint64
+		if i == 0 && srcKind == binarySigned || i > 0 && ordered {
+			var v int64
+			v, n = binary.Varint(body)
+			x = uint64(v)
+			if i > 0 {
+				x += prev
+			}
+		} else {
+			x, n = binary.Uvarint(body)
+			if i > 0 {
+				x += prev
+			}
+		}
+		if n <= 0 {
+			return nil, errBinaryFormat
+		}
+		body = body[n:]
+		prev = x
+
+		storeBits(ptr, size, x)
+		if !integerFits(x, srcKind == binarySigned, loadBits(ptr, size), kind == binarySigned, size) {
+			return nil, fmt.Errorf("mapset: set data element %s does not fit in %s",
+				formatInteger(x, srcKind == binarySigned), reflect.TypeOf(vals[i]))
+		}
+	}
+	if len(body) != 0 {
+		return nil, errBinaryFormat
+	}
+	return vals, nil
+}
+
+func appendUvarint(b []byte, x uint64) []byte {
+	for x >= 0x80 {
+		b = append(b, byte(x)|0x80)
+		x >>= 7
+	}
+	return append(b, byte(x))
+}
+
+func appendVarint(b []byte, x int64) []byte {
+	ux := uint64(x) << 1
+	if x < 0 {
+		ux = ^ux
+	}
+	return appendUvarint(b, ux)
+}
+
+// integerFits reports whether the decoded value x, signed or not, was
+// stored without loss as stored, the bits of an integer of size bytes.
+func integerFits(x uint64, signed bool, stored uint64, storedSigned bool, size int) bool {
+	if signed && int64(x) < 0 {
+		return storedSigned && signExtend(stored, size) == int64(x)
+	}
+	if storedSigned {
+		return signExtend(stored, size) >= 0 && stored == x
+	}
+	return stored == x
+}
+
+func formatInteger(x uint64, signed bool) string {
+	if signed {
+		return fmt.Sprint(int64(x))
+	}
+	return fmt.Sprint(x)
+}
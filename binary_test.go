@@ -0,0 +1,201 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding"
+	"encoding/json"
+	"hash/crc32"
+	"math"
+	"strings"
+	"testing"
+)
+
+type binaryName string
+
+func binaryRoundTrip[T comparable](t *testing.T, s Set[T], u Set[T]) {
+	t.Helper()
+	b, err := s.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if err := u.(encoding.BinaryUnmarshaler).UnmarshalBinary(b); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !u.Equal(s) {
+		t.Errorf("Expected %v, got %v", s, u)
+	}
+}
+
+func Test_BinaryRoundTrip(t *testing.T) {
+	binaryRoundTrip(t, NewSet(3, -1, 0, math.MaxInt, math.MinInt), NewSet[int]())
+	binaryRoundTrip(t, NewThreadUnsafeSet[int8](-128, 127, 0), NewThreadUnsafeSet[int8]())
+	binaryRoundTrip(t, NewSet[uint64](0, 1, math.MaxUint64), NewSet[uint64]())
+	binaryRoundTrip(t, NewSet[uint16](7, 70, 700), NewThreadUnsafeSet[uint16]())
+	binaryRoundTrip(t, NewSet("", "a", "b c", "日本"), NewSet[string]())
+	binaryRoundTrip(t, NewThreadUnsafeSet[binaryName]("x", "y"), NewSet[binaryName]())
+	binaryRoundTrip(t, NewSet[int](), NewSet[int]())
+}
+
+func Test_BinaryOrdered(t *testing.T) {
+	test := func(t *testing.T, s Set[int64], u Set[int64]) {
+		vals := []int64{40, -7, math.MaxInt64, 3, math.MinInt64, 0}
+		s.Append(vals...)
+		u.Add(99)
+		binaryRoundTrip(t, s, u)
+		got := u.ToSlice()
+		for i, v := range vals {
+			if got[i] != v {
+				t.Fatalf("Expected %v, got %v", vals, got)
+			}
+		}
+
+		// Ordered data decodes into any set, and sorted data into an ordered set.
+		binaryRoundTrip(t, s, NewSet[int64]())
+		binaryRoundTrip(t, NewSet(vals...), u)
+	}
+
+	t.Run("Safe", func(t *testing.T) { test(t, NewOrderedSet[int64](), NewOrderedSet[int64]()) })
+	t.Run("Unsafe", func(t *testing.T) { test(t, NewThreadUnsafeOrderedSet[int64](), NewThreadUnsafeOrderedSet[int64]()) })
+
+	s := NewThreadUnsafeOrderedSet("b", "", "a")
+	u := NewThreadUnsafeOrderedSet[string]()
+	binaryRoundTrip(t, s, u)
+	if got := u.ToSlice(); got[0] != "b" || got[1] != "" || got[2] != "a" {
+		t.Errorf("Expected [b  a], got %v", got)
+	}
+
+	var zero threadSafeOrderedSet[uint8]
+	b, _ := NewOrderedSet[uint8](200, 1, 255).(*threadSafeOrderedSet[uint8]).MarshalBinary()
+	if err := zero.UnmarshalBinary(b); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if got := zero.ToSlice(); got[0] != 200 || got[1] != 1 || got[2] != 255 {
+		t.Errorf("Expected [200 1 255], got %v", got)
+	}
+}
+
+func Test_BinaryReplacesContents(t *testing.T) {
+	s := NewSet(1, 2)
+	u := NewSet(9)
+	binaryRoundTrip(t, s, u)
+	if u.ContainsOne(9) {
+		t.Error("UnmarshalBinary should replace the contents of the set")
+	}
+
+	var zero threadSafeSet[int]
+	b, _ := s.(*threadSafeSet[int]).MarshalBinary()
+	if err := zero.UnmarshalBinary(b); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !zero.Equal(s) {
+		t.Errorf("Expected %v, got %v", s, &zero)
+	}
+}
+
+func Test_BinaryDeterministic(t *testing.T) {
+	vals := []int{5, -3, 12, 0, 1 << 40}
+	a, _ := NewSet(vals...).(*threadSafeSet[int]).MarshalBinary()
+	b, _ := NewSet(vals[2], vals[4], vals[0], vals[3], vals[1]).(*threadSafeSet[int]).MarshalBinary()
+	if string(a) != string(b) {
+		t.Error("Equal sets should encode to the same bytes")
+	}
+}
+
+func Test_BinaryCompact(t *testing.T) {
+	s := NewThreadUnsafeSet[uint64]()
+	for i := uint64(0); i < 10000; i++ {
+		s.Add(1_000_000_000 + 3*i)
+	}
+	b, err := s.(*threadUnsafeSet[uint64]).MarshalBinary()
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	j, _ := json.Marshal(s)
+	if len(b) > 10050 || len(b)*5 > len(j) {
+		t.Errorf("Expected a compact encoding, got %d bytes against %d bytes of JSON", len(b), len(j))
+	}
+}
+
+func Test_BinaryWidening(t *testing.T) {
+	b, _ := NewSet[int8](-5, 100).(*threadSafeSet[int8]).MarshalBinary()
+	wide := NewSet[int64]()
+	if err := wide.(*threadSafeSet[int64]).UnmarshalBinary(b); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !wide.Equal(NewSet[int64](-5, 100)) {
+		t.Errorf("Expected {-5, 100}, got %v", wide)
+	}
+
+	if err := NewSet[uint8]().(*threadSafeSet[uint8]).UnmarshalBinary(b); err == nil {
+		t.Error("A negative element should not decode into an unsigned set")
+	}
+
+	b, _ = NewSet[int](1000).(*threadSafeSet[int]).MarshalBinary()
+	if err := NewSet[int8]().(*threadSafeSet[int8]).UnmarshalBinary(b); err == nil {
+		t.Error("An element out of range should not decode")
+	}
+	b, _ = NewSet[uint64](math.MaxUint64).(*threadSafeSet[uint64]).MarshalBinary()
+	if err := NewSet[int64]().(*threadSafeSet[int64]).UnmarshalBinary(b); err == nil {
+		t.Error("An element out of range should not decode")
+	}
+}
+
+func Test_BinaryErrors(t *testing.T) {
+	if _, err := NewSet(1.5).(*threadSafeSet[float64]).MarshalBinary(); err == nil {
+		t.Error("Float sets should not be encodable")
+	}
+
+	good, _ := NewSet(1, 2, 3).(*threadSafeSet[int]).MarshalBinary()
+	u := NewSet[int]().(*threadSafeSet[int])
+
+	corrupt := append([]byte(nil), good...)
+	corrupt[len(corrupt)/2] ^= 0x40
+	if err := u.UnmarshalBinary(corrupt); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("Expected a checksum error, got %v", err)
+	}
+
+	if err := u.UnmarshalBinary(good[:len(good)-1]); err == nil {
+		t.Error("Truncated data should not decode")
+	}
+	if err := u.UnmarshalBinary([]byte("nope")); err == nil {
+		t.Error("Foreign data should not decode")
+	}
+
+	future := append([]byte(nil), good[:len(good)-4]...)
+	future[4] = 2
+	future = appendUint32(future, crc32.ChecksumIEEE(future))
+	if err := u.UnmarshalBinary(future); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Errorf("Expected a version error, got %v", err)
+	}
+
+	if err := NewSet[string]().(*threadSafeSet[string]).UnmarshalBinary(good); err == nil {
+		t.Error("Integer data should not decode into a string set")
+	}
+	if u.Cardinality() != 0 {
+		t.Error("A failed decode should leave the set unchanged")
+	}
+}
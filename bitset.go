@@ -0,0 +1,524 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/bits"
+)
+
+// Unsigned is the set of unsigned integer types a BitSet can hold.
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// BitSet is a set of unsigned integers stored as a dense bitmap, one bit
+// per possible element. For small domains, such as permission IDs or shard
+// numbers, it uses a fraction of the memory of the map-based sets and
+// combines with other BitSets one 64-bit word at a time.
+//
+// The memory used by a BitSet is proportional to its largest element, so
+// it is not suited to sparse or unbounded values, and it cannot hold values
+// greater than MaxBitSetValue. Every method that iterates over the set
+// does so in ascending order, and Pop removes the smallest element. Sets
+// returned by its methods are BitSets too.
+//
+// The zero value is an empty set ready to use. Operations on a BitSet are
+// not thread-safe.
+type BitSet[T Unsigned] struct {
+	words []uint64
+}
+
+// MaxBitSetValue is the largest value a BitSet can hold. A BitSet containing
+// it uses 2 MiB of memory. Adding a greater value to a BitSet, directly or
+// through a union with another kind of set, panics.
+const MaxBitSetValue = 1<<24 - 1
+
+// Assert concrete type:BitSet adheres to Set interface.
+var _ Set[uint] = (*BitSet[uint])(nil)
+
+// NewBitSet creates and returns a new bitset with the given elements.
+// Operations on the resulting set are not thread-safe.
+func NewBitSet[T Unsigned](vals ...T) *BitSet[T] {
+	s := &BitSet[T]{}
+	s.Append(vals...)
+	return s
+}
+
+func (s *BitSet[T]) emptyClone() Set[T] {
+	return &BitSet[T]{}
+}
+
+// bitPosition returns the index of the word holding v and its mask.
+func bitPosition[T Unsigned](v T) (int, uint64) {
+	return int(uint64(v) >> 6), 1 << (uint64(v) & 63)
+}
+
+// trim drops the trailing zero words of s.
+func (s *BitSet[T]) trim() {
+	n := len(s.words)
+	for n > 0 && s.words[n-1] == 0 {
+		n--
+	}
+	s.words = s.words[:n]
+}
+
+// Add adds v to the set and returns whether it was not already present.
+// Add panics if v is greater than MaxBitSetValue.
+func (s *BitSet[T]) Add(v T) bool {
+	if uint64(v) > MaxBitSetValue {
+		panic(fmt.Sprintf("mapset: bitset value %d exceeds MaxBitSetValue", uint64(v)))
+	}
+	w, mask := bitPosition(v)
+	if w >= len(s.words) {
+		if w < cap(s.words) {
+			s.words = s.words[:w+1]
+		} else {
+			words := make([]uint64, w+1, 2*w+1)
+			copy(words, s.words)
+			s.words = words
+		}
+	}
+	if s.words[w]&mask != 0 {
+		return false
+	}
+	s.words[w] |= mask
+	return true
+}
+
+func (s *BitSet[T]) Append(v ...T) int {
+	added := 0
+	for _, val := range v {
+		if s.Add(val) {
+			added++
+		}
+	}
+	return added
+}
+
+// Cardinality returns the number of elements in the set, counting the bits
+// set in every word.
+func (s *BitSet[T]) Cardinality() int {
+	n := 0
+	for _, w := range s.words {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+func (s *BitSet[T]) Clear() {
+	s.words = s.words[:0]
+}
+
+func (s *BitSet[T]) Clone() Set[T] {
+	words := make([]uint64, len(s.words))
+	copy(words, s.words)
+	return &BitSet[T]{words: words}
+}
+
+func (s *BitSet[T]) Contains(v ...T) bool {
+	for _, val := range v {
+		if !s.ContainsOne(val) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *BitSet[T]) ContainsOne(v T) bool {
+	w, mask := bitPosition(v)
+	return w < len(s.words) && s.words[w]&mask != 0
+}
+
+func (s *BitSet[T]) ContainsAny(v ...T) bool {
+	for _, val := range v {
+		if s.ContainsOne(val) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *BitSet[T]) ContainsAnyElement(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	if o, ok := c.(*BitSet[T]); ok {
+		for i := 0; i < len(s.words) && i < len(o.words); i++ {
+			if s.words[i]&o.words[i] != 0 {
+				return true
+			}
+		}
+		return false
+	}
+	return containsAnyOf[T](s, c)
+}
+
+func (s *BitSet[T]) Difference(other Set[T]) Set[T] {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	diff := s.Clone().(*BitSet[T])
+	diff.differenceUpdate(c)
+	return diff
+}
+
+// Each calls cb on every element in ascending order.
+func (s *BitSet[T]) Each(cb func(T) bool) {
+	for i, w := range s.words {
+		for w != 0 {
+			if cb(T(i<<6 + bits.TrailingZeros64(w))) {
+				return
+			}
+			w &= w - 1
+		}
+	}
+}
+
+func (s *BitSet[T]) Equal(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	if o, ok := c.(*BitSet[T]); ok {
+		short, long := s.words, o.words
+		if len(short) > len(long) {
+			short, long = long, short
+		}
+		for i := range short {
+			if short[i] != long[i] {
+				return false
+			}
+		}
+		for _, w := range long[len(short):] {
+			if w != 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return equalCollections[T](s, c)
+}
+
+func (s *BitSet[T]) Intersect(other Set[T]) Set[T] {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	intersection := s.Clone().(*BitSet[T])
+	intersection.intersectUpdate(c)
+	return intersection
+}
+
+func (s *BitSet[T]) IsEmpty() bool {
+	for _, w := range s.words {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *BitSet[T]) IsProperSubset(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.isSubset(c) && !s.isSuperset(c)
+}
+
+func (s *BitSet[T]) IsProperSuperset(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.isSuperset(c) && !s.isSubset(c)
+}
+
+func (s *BitSet[T]) IsSubset(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.isSubset(c)
+}
+
+// private version of IsSubset for an already locked collection c
+func (s *BitSet[T]) isSubset(c Collection[T]) bool {
+	if o, ok := c.(*BitSet[T]); ok {
+		for i, w := range s.words {
+			if i < len(o.words) {
+				w &^= o.words[i]
+			}
+			if w != 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return isSubsetOf[T](s, c)
+}
+
+func (s *BitSet[T]) IsSuperset(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.isSuperset(c)
+}
+
+// private version of IsSuperset for an already locked collection c
+func (s *BitSet[T]) isSuperset(c Collection[T]) bool {
+	if o, ok := c.(*BitSet[T]); ok {
+		return o.isSubset(s)
+	}
+	return isSubsetOf[T](c, s)
+}
+
+func (s *BitSet[T]) Iter() <-chan T {
+	return iterCollection[T](s)
+}
+
+func (s *BitSet[T]) Iterator() *Iterator[T] {
+	return iteratorCollection[T](s)
+}
+
+// Pop removes and returns the smallest element in case set is not empty,
+// or nil-value of T if set is already empty
+func (s *BitSet[T]) Pop() (v T, ok bool) {
+	for i, w := range s.words {
+		if w != 0 {
+			tz := bits.TrailingZeros64(w)
+			s.words[i] &^= 1 << tz
+			return T(i<<6 + tz), true
+		}
+	}
+	return v, false
+}
+
+func (s *BitSet[T]) PopN(n int) (items []T, count int) {
+	if n <= 0 || s.IsEmpty() {
+		return make([]T, 0), 0
+	}
+	if sn := s.Cardinality(); n > sn {
+		n = sn
+	}
+
+	items = make([]T, 0, n)
+	for count < n {
+		v, _ := s.Pop()
+		items = append(items, v)
+		count++
+	}
+	return items, count
+}
+
+func (s *BitSet[T]) Remove(v T) {
+	if w, mask := bitPosition(v); w < len(s.words) {
+		s.words[w] &^= mask
+	}
+}
+
+func (s *BitSet[T]) RemoveAll(i ...T) {
+	for _, elem := range i {
+		s.Remove(elem)
+	}
+}
+
+func (s *BitSet[T]) String() string {
+	return formatCollection[T](s)
+}
+
+func (s *BitSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	sd := s.Clone().(*BitSet[T])
+	sd.symmetricDifferenceUpdate(c)
+	return sd
+}
+
+func (s *BitSet[T]) ToSlice() []T {
+	return collectionToSlice[T](s)
+}
+
+func (s *BitSet[T]) Union(other Set[T]) Set[T] {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	unionedSet := s.Clone().(*BitSet[T])
+	unionedSet.unionUpdate(c)
+	return unionedSet
+}
+
+func (s *BitSet[T]) UnionUpdate(other Set[T]) int {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.unionUpdate(c)
+}
+
+// private version of UnionUpdate for an already locked collection c
+func (s *BitSet[T]) unionUpdate(c Collection[T]) int {
+	prevLen := s.Cardinality()
+	if o, ok := c.(*BitSet[T]); ok {
+		if len(o.words) > len(s.words) {
+			words := make([]uint64, len(o.words))
+			copy(words, s.words)
+			s.words = words
+		}
+		for i, w := range o.words {
+			s.words[i] |= w
+		}
+	} else {
+		c.Each(func(elem T) bool {
+			s.Add(elem)
+			return false
+		})
+	}
+	return s.Cardinality() - prevLen
+}
+
+func (s *BitSet[T]) IntersectUpdate(other Set[T]) int {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.intersectUpdate(c)
+}
+
+// private version of IntersectUpdate for an already locked collection c
+func (s *BitSet[T]) intersectUpdate(c Collection[T]) int {
+	prevLen := s.Cardinality()
+	if o, ok := c.(*BitSet[T]); ok {
+		for i := range s.words {
+			if i < len(o.words) {
+				s.words[i] &= o.words[i]
+			} else {
+				s.words[i] = 0
+			}
+		}
+	} else {
+		s.Each(func(elem T) bool {
+			if !c.ContainsOne(elem) {
+				s.Remove(elem)
+			}
+			return false
+		})
+	}
+	s.trim()
+	return prevLen - s.Cardinality()
+}
+
+func (s *BitSet[T]) DifferenceUpdate(other Set[T]) int {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.differenceUpdate(c)
+}
+
+// private version of DifferenceUpdate for an already locked collection c
+func (s *BitSet[T]) differenceUpdate(c Collection[T]) int {
+	prevLen := s.Cardinality()
+	if o, ok := c.(*BitSet[T]); ok {
+		if o == s {
+			s.Clear()
+			return prevLen
+		}
+		for i := 0; i < len(s.words) && i < len(o.words); i++ {
+			s.words[i] &^= o.words[i]
+		}
+	} else {
+		c.Each(func(elem T) bool {
+			s.Remove(elem)
+			return false
+		})
+	}
+	s.trim()
+	return prevLen - s.Cardinality()
+}
+
+func (s *BitSet[T]) SymmetricDifferenceUpdate(other Set[T]) (added, removed int) {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.symmetricDifferenceUpdate(c)
+}
+
+// private version of SymmetricDifferenceUpdate for an already locked collection c
+func (s *BitSet[T]) symmetricDifferenceUpdate(c Collection[T]) (added, removed int) {
+	o, ok := c.(*BitSet[T])
+	if !ok {
+		c.Each(func(elem T) bool {
+			if s.ContainsOne(elem) {
+				s.Remove(elem)
+				removed++
+			} else if s.Add(elem) {
+				added++
+			}
+			return false
+		})
+		s.trim()
+		return added, removed
+	}
+
+	if o == s {
+		removed = s.Cardinality()
+		s.Clear()
+		return added, removed
+	}
+	if len(o.words) > len(s.words) {
+		words := make([]uint64, len(o.words))
+		copy(words, s.words)
+		s.words = words
+	}
+	for i, w := range o.words {
+		added += bits.OnesCount64(w &^ s.words[i])
+		removed += bits.OnesCount64(w & s.words[i])
+		s.words[i] ^= w
+	}
+	s.trim()
+	return added, removed
+}
+
+// MarshalJSON creates a JSON array from the set in ascending order, it
+// marshals all elements
+func (s *BitSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalCollectionJSON[T](s)
+}
+
+// UnmarshalJSON adds the elements of a JSON array to the set. It returns an
+// error without modifying the set if an element is greater than
+// MaxBitSetValue.
+func (s *BitSet[T]) UnmarshalJSON(b []byte) error {
+	var i []T
+	err := json.Unmarshal(b, &i)
+	if err != nil {
+		return err
+	}
+	for _, v := range i {
+		if uint64(v) > MaxBitSetValue {
+			return fmt.Errorf("mapset: bitset value %d exceeds MaxBitSetValue", uint64(v))
+		}
+	}
+	s.Append(i...)
+
+	return nil
+}
@@ -0,0 +1,219 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// Assert interface: a BitSet can be updated in place.
+var _ Updater[uint] = (*BitSet[uint])(nil)
+
+func Test_BitSetBasics(t *testing.T) {
+	var s BitSet[uint16]
+	if !s.IsEmpty() || s.Cardinality() != 0 {
+		t.Error("The zero BitSet should be empty")
+	}
+	if !s.Add(130) || s.Add(130) {
+		t.Error("Add should report whether the element was new")
+	}
+	if n := s.Append(3, 64, 3, 63); n != 3 {
+		t.Errorf("Append should add 3 new elements, added %d", n)
+	}
+	if s.Cardinality() != 4 {
+		t.Errorf("Cardinality should be 4, got %d", s.Cardinality())
+	}
+	if !s.Contains(3, 63, 64, 130) || s.ContainsOne(65) || s.ContainsOne(60000) {
+		t.Error("Contains reported the wrong membership")
+	}
+	if got := s.ToSlice(); !reflect.DeepEqual(got, []uint16{3, 63, 64, 130}) {
+		t.Errorf("ToSlice should be ascending, got %v", got)
+	}
+	if got := s.String(); got != "Set{3, 63, 64, 130}" {
+		t.Errorf("String should be ascending, got %s", got)
+	}
+
+	s.Remove(63)
+	s.Remove(60000)
+	if v, ok := s.Pop(); !ok || v != 3 {
+		t.Errorf("Pop should remove the smallest element, got %v", v)
+	}
+	if items, n := s.PopN(5); n != 2 || !reflect.DeepEqual(items, []uint16{64, 130}) {
+		t.Errorf("PopN should remove the smallest elements, got %v", items)
+	}
+	if _, ok := s.Pop(); ok {
+		t.Error("Pop on an empty set should fail")
+	}
+}
+
+func Test_BitSetWordAlgebra(t *testing.T) {
+	a := NewBitSet[uint](1, 2, 3, 200)
+	b := NewBitSet[uint](2, 3, 4)
+
+	assertEqual[uint](a.Union(b), NewBitSet[uint](1, 2, 3, 4, 200), t)
+	assertEqual[uint](a.Intersect(b), NewBitSet[uint](2, 3), t)
+	assertEqual[uint](a.Difference(b), NewBitSet[uint](1, 200), t)
+	assertEqual[uint](b.Difference(a), NewBitSet[uint](4), t)
+	assertEqual[uint](a.SymmetricDifference(b), NewBitSet[uint](1, 4, 200), t)
+
+	if _, ok := a.Union(b).(*BitSet[uint]); !ok {
+		t.Error("Union of two BitSets should be a BitSet")
+	}
+	if !a.Intersect(b).Equal(b.Intersect(a)) {
+		t.Error("Equal should ignore trailing empty words")
+	}
+	if !NewBitSet[uint](2, 3).IsProperSubset(a) || a.IsSubset(b) || !a.IsSuperset(NewBitSet[uint](200)) {
+		t.Error("Subset relations are wrong")
+	}
+	if !a.ContainsAnyElement(b) || a.ContainsAnyElement(NewBitSet[uint](5, 500)) {
+		t.Error("ContainsAnyElement reported the wrong result")
+	}
+
+	c := a.Clone().(*BitSet[uint])
+	if added, removed := c.SymmetricDifferenceUpdate(b); added != 1 || removed != 2 {
+		t.Errorf("SymmetricDifferenceUpdate should add 1 and remove 2, got %d and %d", added, removed)
+	}
+	if n := c.DifferenceUpdate(c); n != 3 || !c.IsEmpty() {
+		t.Errorf("DifferenceUpdate with itself should empty the set, removed %d", n)
+	}
+	if n := c.UnionUpdate(a); n != 4 {
+		t.Errorf("UnionUpdate should add 4 elements, added %d", n)
+	}
+	if n := c.IntersectUpdate(b); n != 2 {
+		t.Errorf("IntersectUpdate should remove 2 elements, removed %d", n)
+	}
+}
+
+func Test_BitSetMatchesMapSet(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	random := func() ([]uint32, *BitSet[uint32], Set[uint32]) {
+		vals := make([]uint32, r.Intn(300))
+		for i := range vals {
+			vals[i] = uint32(r.Intn(1000))
+		}
+		return vals, NewBitSet(vals...), NewThreadUnsafeSet(vals...)
+	}
+
+	for i := 0; i < 50; i++ {
+		_, a, ma := random()
+		_, b, mb := random()
+
+		for _, op := range []struct {
+			name     string
+			got, exp Set[uint32]
+		}{
+			{"Union", a.Union(b), ma.Union(mb)},
+			{"Intersect", a.Intersect(b), ma.Intersect(mb)},
+			{"Difference", a.Difference(b), ma.Difference(mb)},
+			{"SymmetricDifference", a.SymmetricDifference(b), ma.SymmetricDifference(mb)},
+			{"Union with map set", a.Union(mb), ma.Union(mb)},
+			{"Intersect with map set", a.Intersect(mb), ma.Intersect(mb)},
+		} {
+			if !op.got.Equal(op.exp) || op.got.Cardinality() != op.exp.Cardinality() {
+				t.Fatalf("%s mismatch: got %v, expected %v", op.name, op.got, op.exp)
+			}
+		}
+		if a.IsSubset(b) != ma.IsSubset(mb) || a.IsSuperset(b) != ma.IsSuperset(mb) {
+			t.Fatal("Subset relations should match the map-based set")
+		}
+		if !ma.Equal(a) {
+			t.Fatal("A map-based set should equal a BitSet with the same elements")
+		}
+	}
+}
+
+func Test_BitSetJSON(t *testing.T) {
+	s := NewBitSet[uint8](200, 7, 64)
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+	if string(b) != "[7,64,200]" {
+		t.Errorf("Expected [7,64,200], got %s", b)
+	}
+
+	u := &BitSet[uint8]{}
+	if err := json.Unmarshal(b, u); err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+	assertEqual[uint8](u, s, t)
+
+	m := NewSet[uint8]()
+	if err := json.Unmarshal(b, m); err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+	assertEqual[uint8](m, s, t)
+}
+
+func Test_BitSetMaxValue(t *testing.T) {
+	panics := func(name string, f func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s should panic on values above MaxBitSetValue", name)
+			}
+		}()
+		f()
+	}
+
+	s := NewBitSet[uint64](1)
+	panics("Add", func() { s.Add(MaxBitSetValue + 1) })
+	panics("Add", func() { s.Add(1<<64 - 1) })
+	if !s.Add(MaxBitSetValue) || !s.ContainsOne(MaxBitSetValue) {
+		t.Error("Add should accept MaxBitSetValue")
+	}
+	if s.Cardinality() != 2 {
+		t.Errorf("Expected 2 elements, got %d", s.Cardinality())
+	}
+
+	foreign := NewThreadUnsafeSet[uint64](2, 1<<30)
+	panics("Union", func() { NewBitSet[uint64](1, 2).Union(foreign) })
+	panics("UnionUpdate", func() { NewBitSet[uint64](1, 2).UnionUpdate(foreign) })
+	panics("SymmetricDifference", func() { NewBitSet[uint64](1, 2).SymmetricDifference(foreign) })
+	panics("SymmetricDifferenceUpdate", func() { NewBitSet[uint64](1, 2).SymmetricDifferenceUpdate(foreign) })
+
+	inter := NewBitSet[uint64](1, 2).Intersect(foreign)
+	if !inter.Equal(NewBitSet[uint64](2)) {
+		t.Errorf("Expected intersection {2}, got %v", inter)
+	}
+	diff := NewBitSet[uint64](1, 2).Difference(foreign)
+	if !diff.Equal(NewBitSet[uint64](1)) {
+		t.Errorf("Expected difference {1}, got %v", diff)
+	}
+
+	for _, in := range []string{"[18446744073709551615]", "[1,4000000000]"} {
+		u := NewBitSet[uint64](3)
+		if err := json.Unmarshal([]byte(in), u); err == nil {
+			t.Errorf("Unmarshalling %s should fail", in)
+		}
+		if !u.Equal(NewBitSet[uint64](3)) {
+			t.Errorf("Failed unmarshal of %s should leave the set unchanged, got %v", in, u)
+		}
+	}
+}
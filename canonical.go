@@ -0,0 +1,143 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+// StringSorted returns the string representation of s, as returned by its
+// String method, with the elements in ascending order, so that equal sets
+// are represented alike. See SortedElements for the order used.
+func StringSorted[T comparable](s ReadOnlySet[T]) string {
+	items := make([]string, 0, s.Cardinality())
+	for _, elem := range SortedElements[T](s) {
+		items = append(items, fmt.Sprintf("%v", elem))
+	}
+	return fmt.Sprintf("Set{%s}", strings.Join(items, ", "))
+}
+
+// MarshalJSONSorted returns s encoded as a JSON array, like its MarshalJSON
+// method, with the elements in ascending order, so that equal sets are
+// encoded alike. See SortedElements for the order used.
+func MarshalJSONSorted[T comparable](s ReadOnlySet[T]) ([]byte, error) {
+	vals := SortedElements[T](s)
+	return marshalCollectionJSON[T](sliceCollection[T](vals))
+}
+
+// SortedElements returns the elements of s in a deterministic order.
+// Elements of integer, floating-point and string types, including types
+// defined on them, are sorted in ascending order, with NaNs first. Elements
+// of any other type are sorted by their %v representation, then by their
+// %#v representation.
+func SortedElements[T comparable](s ReadOnlySet[T]) []T {
+	vals := collectionToSlice[T](s)
+	if less := naturalLess[T](); less != nil {
+		sort.Slice(vals, func(i, j int) bool { return less(vals[i], vals[j]) })
+		return vals
+	}
+
+	type keyed struct {
+		key, alt string
+		val      T
+	}
+	items := make([]keyed, len(vals))
+	for i, v := range vals {
+		items[i] = keyed{fmt.Sprintf("%v", v), fmt.Sprintf("%#v", v), v}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].key != items[j].key {
+			return items[i].key < items[j].key
+		}
+		return items[i].alt < items[j].alt
+	})
+	for i := range items {
+		vals[i] = items[i].val
+	}
+	return vals
+}
+
+// naturalLess returns the natural ordering of T if its underlying type is
+// an integer, floating-point or string type, and nil otherwise.
+func naturalLess[T comparable]() func(a, b T) bool {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	size := int(t.Size())
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b T) bool {
+			return signExtend(loadBits(unsafe.Pointer(&a), size), size) <
+				signExtend(loadBits(unsafe.Pointer(&b), size), size)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b T) bool {
+			return loadBits(unsafe.Pointer(&a), size) < loadBits(unsafe.Pointer(&b), size)
+		}
+	case reflect.Float32:
+		return func(a, b T) bool {
+			x, y := *(*float32)(unsafe.Pointer(&a)), *(*float32)(unsafe.Pointer(&b))
+			return x < y || x != x && y == y
+		}
+	case reflect.Float64:
+		return func(a, b T) bool {
+			x, y := *(*float64)(unsafe.Pointer(&a)), *(*float64)(unsafe.Pointer(&b))
+			return x < y || x != x && y == y
+		}
+	case reflect.String:
+		return func(a, b T) bool {
+			return *(*string)(unsafe.Pointer(&a)) < *(*string)(unsafe.Pointer(&b))
+		}
+	}
+	return nil
+}
+
+// sliceCollection is a Collection of distinct elements held in a slice,
+// iterated in order.
+type sliceCollection[T comparable] []T
+
+func (s sliceCollection[T]) Cardinality() int {
+	return len(s)
+}
+
+func (s sliceCollection[T]) ContainsOne(val T) bool {
+	for _, v := range s {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
+
+func (s sliceCollection[T]) Each(cb func(T) bool) {
+	for _, v := range s {
+		if cb(v) {
+			break
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"math"
+	"testing"
+)
+
+type canonicalID int32
+
+type canonicalPoint struct {
+	X, Y int
+}
+
+func Test_StringSorted(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{StringSorted[int](NewSet(10, -3, 2, 0)), "Set{-3, 0, 2, 10}"},
+		{StringSorted[uint64](NewThreadUnsafeSet[uint64](math.MaxUint64, 1)), "Set{1, 18446744073709551615}"},
+		{StringSorted[canonicalID](NewSet[canonicalID](-1, -100, 7)), "Set{-100, -1, 7}"},
+		{StringSorted[float64](NewSet(2.5, math.NaN(), -1, math.Inf(1))), "Set{NaN, -1, 2.5, +Inf}"},
+		{StringSorted[string](NewSet("b", "a", "B")), "Set{B, a, b}"},
+		{StringSorted[canonicalPoint](NewSet(canonicalPoint{2, 1}, canonicalPoint{1, 2})), "Set{{1 2}, {2 1}}"},
+		{StringSorted[int](NewSet[int]()), "Set{}"},
+	}
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Errorf("Expected %s, got %s", test.want, test.got)
+		}
+	}
+}
+
+func Test_MarshalJSONSorted(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		b, err := MarshalJSONSorted[string](NewSet("c", "a", "b", "d", "e"))
+		if err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		if string(b) != `["a","b","c","d","e"]` {
+			t.Errorf(`Expected ["a","b","c","d","e"], got %s`, b)
+		}
+	}
+
+	b, err := MarshalJSONSorted[uint8](NewSet[uint8](200, 3))
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if string(b) != `[3,200]` {
+		t.Errorf(`Expected [3,200], got %s`, b)
+	}
+
+	b, err = MarshalJSONSorted[int](NewSet[int]())
+	if err != nil || string(b) != `[]` {
+		t.Errorf(`Expected [], got %s, %v`, b, err)
+	}
+}
+
+func Test_SortedElementsTies(t *testing.T) {
+	type pair struct {
+		A, B string
+	}
+
+	// Both elements print as {a b }, so they are ordered by their %#v
+	// representation.
+	got := SortedElements[pair](NewSet(pair{"a", "b "}, pair{"a b", ""}))
+	if got[0] != (pair{"a b", ""}) || got[1] != (pair{"a", "b "}) {
+		t.Errorf("Expected [{a b } {a b }], got %#v", got)
+	}
+}
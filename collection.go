@@ -0,0 +1,147 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// The helpers below implement the parts of the Set interface that only
+// need the Collection methods. They are shared by the implementations that
+// have no faster way of their own to answer them.
+
+// containsAnyOf returns whether at least one element of c is in s.
+func containsAnyOf[T comparable](s, c Collection[T]) bool {
+	// loop over smaller collection
+	if s.Cardinality() > c.Cardinality() {
+		s, c = c, s
+	}
+	found := false
+	s.Each(func(elem T) bool {
+		found = c.ContainsOne(elem)
+		return found
+	})
+	return found
+}
+
+// isSubsetOf returns whether every element of s is in c.
+func isSubsetOf[T comparable](s, c Collection[T]) bool {
+	if s.Cardinality() > c.Cardinality() {
+		return false
+	}
+	subset := true
+	s.Each(func(elem T) bool {
+		subset = c.ContainsOne(elem)
+		return !subset
+	})
+	return subset
+}
+
+// equalCollections returns whether s and c hold the same elements.
+func equalCollections[T comparable](s, c Collection[T]) bool {
+	return s.Cardinality() == c.Cardinality() && isSubsetOf(s, c)
+}
+
+// iterCollection returns a channel yielding every element of c.
+func iterCollection[T comparable](c Collection[T]) <-chan T {
+	ch := make(chan T)
+	go func() {
+		c.Each(func(elem T) bool {
+			ch <- elem
+			return false
+		})
+		close(ch)
+	}()
+
+	return ch
+}
+
+// iteratorCollection returns an Iterator over every element of c.
+func iteratorCollection[T comparable](c Collection[T]) *Iterator[T] {
+	iterator, ch, stopCh := newIterator[T]()
+
+	go func() {
+		c.Each(func(elem T) bool {
+			select {
+			case <-stopCh:
+				return true
+			case ch <- elem:
+				return false
+			}
+		})
+		close(ch)
+	}()
+
+	return iterator
+}
+
+// collectionToSlice returns the elements of c as a slice.
+func collectionToSlice[T comparable](c Collection[T]) []T {
+	items := make([]T, 0, c.Cardinality())
+	c.Each(func(elem T) bool {
+		items = append(items, elem)
+		return false
+	})
+	return items
+}
+
+// formatCollection returns the string representation of c used by the
+// String method of every set.
+func formatCollection[T comparable](c Collection[T]) string {
+	items := make([]string, 0, c.Cardinality())
+	c.Each(func(elem T) bool {
+		items = append(items, fmt.Sprintf("%v", elem))
+		return false
+	})
+	return fmt.Sprintf("Set{%s}", strings.Join(items, ", "))
+}
+
+// marshalCollectionJSON returns c encoded as a JSON array, in iteration
+// order.
+func marshalCollectionJSON[T comparable](c Collection[T]) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	buf.WriteByte('[')
+	c.Each(func(elem T) bool {
+		var b []byte
+		if b, err = json.Marshal(elem); err != nil {
+			return true
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(b)
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,328 @@
+//go:build go1.19
+// +build go1.19
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+)
+
+// CopyOnWriteSet is a thread-safe set tuned for data that is read far more
+// often than it changes, such as a blocklist checked on every request.
+//
+// The elements live in an immutable snapshot that readers load from an
+// atomic pointer, so reads never lock and never write to shared memory.
+// Writers are serialized by a mutex; each write copies the current
+// snapshot, applies the change to the copy and publishes it with a single
+// atomic swap, which makes every write O(n). Use Update to apply many
+// changes with one copy and one swap.
+//
+// Operations reading the whole set, such as Each, Equal or ToSlice, see a
+// single snapshot, even if writers publish new ones meanwhile. Sets
+// returned by its methods are CopyOnWriteSets too.
+//
+// The zero value is an empty set ready to use.
+type CopyOnWriteSet[T comparable] struct {
+	mu   sync.Mutex
+	snap atomic.Pointer[threadUnsafeSet[T]]
+}
+
+// Assert concrete type:CopyOnWriteSet adheres to Set interface.
+var _ Set[string] = (*CopyOnWriteSet[string])(nil)
+
+// NewCopyOnWriteSet creates and returns a new copy-on-write set with the
+// given elements. Operations on the resulting set are thread-safe.
+func NewCopyOnWriteSet[T comparable](vals ...T) *CopyOnWriteSet[T] {
+	s := newThreadUnsafeSetWithSize[T](len(vals))
+	s.Append(vals...)
+	return newCopyOnWriteSet(s)
+}
+
+func newCopyOnWriteSet[T comparable](snap *threadUnsafeSet[T]) *CopyOnWriteSet[T] {
+	c := &CopyOnWriteSet[T]{}
+	c.snap.Store(snap)
+	return c
+}
+
+func (c *CopyOnWriteSet[T]) emptyClone() Set[T] {
+	return NewCopyOnWriteSet[T]()
+}
+
+// load returns the current snapshot, which must not be modified.
+func (c *CopyOnWriteSet[T]) load() *threadUnsafeSet[T] {
+	if s := c.snap.Load(); s != nil {
+		return s
+	}
+	return newThreadUnsafeSet[T]()
+}
+
+func (c *CopyOnWriteSet[T]) snapshot() Collection[T] {
+	return c.load()
+}
+
+// write publishes a copy of the current snapshot modified by fn, unless fn
+// reports that it changed nothing. It returns the result of fn.
+func (c *CopyOnWriteSet[T]) write(fn func(next *threadUnsafeSet[T]) bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	next := c.load().Clone().(*threadUnsafeSet[T])
+	if !fn(next) {
+		return false
+	}
+	c.snap.Store(next)
+	return true
+}
+
+// Update applies fn to a private copy of the set and then publishes the
+// result with a single swap, so that readers see either none or all of the
+// changes. Other writers wait until fn returns. fn must not retain s.
+func (c *CopyOnWriteSet[T]) Update(fn func(s Set[T])) {
+	c.write(func(next *threadUnsafeSet[T]) bool {
+		fn(next)
+		return true
+	})
+}
+
+func (c *CopyOnWriteSet[T]) Add(v T) bool {
+	if c.load().ContainsOne(v) {
+		return false
+	}
+	return c.write(func(next *threadUnsafeSet[T]) bool {
+		return next.Add(v)
+	})
+}
+
+func (c *CopyOnWriteSet[T]) Append(v ...T) int {
+	added := 0
+	c.write(func(next *threadUnsafeSet[T]) bool {
+		added = next.Append(v...)
+		return added > 0
+	})
+	return added
+}
+
+func (c *CopyOnWriteSet[T]) Cardinality() int {
+	return c.load().Cardinality()
+}
+
+func (c *CopyOnWriteSet[T]) Clear() {
+	c.mu.Lock()
+	c.snap.Store(newThreadUnsafeSet[T]())
+	c.mu.Unlock()
+}
+
+// Clone returns a copy-on-write set sharing the current snapshot, which
+// takes constant time.
+func (c *CopyOnWriteSet[T]) Clone() Set[T] {
+	return newCopyOnWriteSet(c.load())
+}
+
+func (c *CopyOnWriteSet[T]) Contains(v ...T) bool {
+	return c.load().Contains(v...)
+}
+
+func (c *CopyOnWriteSet[T]) ContainsOne(v T) bool {
+	return c.load().ContainsOne(v)
+}
+
+func (c *CopyOnWriteSet[T]) ContainsAny(v ...T) bool {
+	return c.load().ContainsAny(v...)
+}
+
+func (c *CopyOnWriteSet[T]) ContainsAnyElement(other Set[T]) bool {
+	return c.load().ContainsAnyElement(other)
+}
+
+func (c *CopyOnWriteSet[T]) Difference(other Set[T]) Set[T] {
+	return newCopyOnWriteSet(c.load().Difference(other).(*threadUnsafeSet[T]))
+}
+
+func (c *CopyOnWriteSet[T]) Each(cb func(T) bool) {
+	c.load().Each(cb)
+}
+
+func (c *CopyOnWriteSet[T]) Equal(other Set[T]) bool {
+	return c.load().Equal(other)
+}
+
+func (c *CopyOnWriteSet[T]) Intersect(other Set[T]) Set[T] {
+	return newCopyOnWriteSet(c.load().Intersect(other).(*threadUnsafeSet[T]))
+}
+
+func (c *CopyOnWriteSet[T]) IsEmpty() bool {
+	return c.load().IsEmpty()
+}
+
+func (c *CopyOnWriteSet[T]) IsProperSubset(other Set[T]) bool {
+	return c.load().IsProperSubset(other)
+}
+
+func (c *CopyOnWriteSet[T]) IsProperSuperset(other Set[T]) bool {
+	return c.load().IsProperSuperset(other)
+}
+
+func (c *CopyOnWriteSet[T]) IsSubset(other Set[T]) bool {
+	return c.load().IsSubset(other)
+}
+
+func (c *CopyOnWriteSet[T]) IsSuperset(other Set[T]) bool {
+	return c.load().IsSuperset(other)
+}
+
+func (c *CopyOnWriteSet[T]) Iter() <-chan T {
+	return c.load().Iter()
+}
+
+func (c *CopyOnWriteSet[T]) Iterator() *Iterator[T] {
+	return c.load().Iterator()
+}
+
+func (c *CopyOnWriteSet[T]) Pop() (v T, ok bool) {
+	c.write(func(next *threadUnsafeSet[T]) bool {
+		v, ok = next.Pop()
+		return ok
+	})
+	return v, ok
+}
+
+func (c *CopyOnWriteSet[T]) PopN(n int) (items []T, count int) {
+	c.write(func(next *threadUnsafeSet[T]) bool {
+		items, count = next.PopN(n)
+		return count > 0
+	})
+	return items, count
+}
+
+func (c *CopyOnWriteSet[T]) Remove(v T) {
+	if !c.load().ContainsOne(v) {
+		return
+	}
+	c.write(func(next *threadUnsafeSet[T]) bool {
+		if !next.ContainsOne(v) {
+			return false
+		}
+		next.Remove(v)
+		return true
+	})
+}
+
+func (c *CopyOnWriteSet[T]) RemoveAll(i ...T) {
+	c.write(func(next *threadUnsafeSet[T]) bool {
+		prevLen := next.Cardinality()
+		next.RemoveAll(i...)
+		return next.Cardinality() < prevLen
+	})
+}
+
+func (c *CopyOnWriteSet[T]) String() string {
+	return c.load().String()
+}
+
+func (c *CopyOnWriteSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	return newCopyOnWriteSet(c.load().SymmetricDifference(other).(*threadUnsafeSet[T]))
+}
+
+func (c *CopyOnWriteSet[T]) ToSlice() []T {
+	return c.load().ToSlice()
+}
+
+func (c *CopyOnWriteSet[T]) Union(other Set[T]) Set[T] {
+	return newCopyOnWriteSet(c.load().Union(other).(*threadUnsafeSet[T]))
+}
+
+// The update operations read other before taking the writer lock, so that
+// its own lock, if any, is never requested while the writer lock is held.
+
+func (c *CopyOnWriteSet[T]) UnionUpdate(other Set[T]) int {
+	o, unlock := lockOther[T](other)
+	defer unlock()
+
+	added := 0
+	c.write(func(next *threadUnsafeSet[T]) bool {
+		added = next.unionUpdate(o)
+		return added > 0
+	})
+	return added
+}
+
+func (c *CopyOnWriteSet[T]) IntersectUpdate(other Set[T]) int {
+	o, unlock := lockOther[T](other)
+	defer unlock()
+
+	removed := 0
+	c.write(func(next *threadUnsafeSet[T]) bool {
+		removed = next.intersectUpdate(o)
+		return removed > 0
+	})
+	return removed
+}
+
+func (c *CopyOnWriteSet[T]) DifferenceUpdate(other Set[T]) int {
+	o, unlock := lockOther[T](other)
+	defer unlock()
+
+	removed := 0
+	c.write(func(next *threadUnsafeSet[T]) bool {
+		removed = next.differenceUpdate(o)
+		return removed > 0
+	})
+	return removed
+}
+
+func (c *CopyOnWriteSet[T]) SymmetricDifferenceUpdate(other Set[T]) (added, removed int) {
+	o, unlock := lockOther[T](other)
+	defer unlock()
+
+	c.write(func(next *threadUnsafeSet[T]) bool {
+		added, removed = next.symmetricDifferenceUpdate(o)
+		return added > 0 || removed > 0
+	})
+	return added, removed
+}
+
+// MarshalJSON creates a JSON array from the current snapshot, it marshals
+// all elements
+func (c *CopyOnWriteSet[T]) MarshalJSON() ([]byte, error) {
+	return c.load().MarshalJSON()
+}
+
+// UnmarshalJSON adds the elements of a JSON array to the set with a single
+// swap.
+func (c *CopyOnWriteSet[T]) UnmarshalJSON(b []byte) error {
+	var i []T
+	err := json.Unmarshal(b, &i)
+	if err != nil {
+		return err
+	}
+	c.Append(i...)
+
+	return nil
+}
@@ -0,0 +1,161 @@
+//go:build go1.19
+// +build go1.19
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+)
+
+// Assert interface: a CopyOnWriteSet can be updated in place.
+var _ Updater[int] = (*CopyOnWriteSet[int])(nil)
+
+func Test_CopyOnWriteSetOperations(t *testing.T) {
+	var zero CopyOnWriteSet[int]
+	if !zero.IsEmpty() || !zero.Add(1) || zero.Add(1) || !zero.ContainsOne(1) {
+		t.Error("The zero CopyOnWriteSet should be an empty set ready to use")
+	}
+
+	a := NewCopyOnWriteSet(1, 2, 3)
+	b := NewSet(3, 4)
+	assertEqual(a.Union(b), NewSet(1, 2, 3, 4), t)
+	assertEqual(a.Intersect(b), NewSet(3), t)
+	assertEqual(a.Difference(b), NewSet(1, 2), t)
+	assertEqual(a.SymmetricDifference(b), NewSet(1, 2, 4), t)
+	if _, ok := a.Union(b).(*CopyOnWriteSet[int]); !ok {
+		t.Error("Results should be copy-on-write sets like the receiver")
+	}
+	if !b.ContainsAnyElement(a) || !NewSet(1, 2, 3).Equal(a) || !a.IsSuperset(NewThreadUnsafeSet(1)) {
+		t.Error("Other sets should read the copy-on-write set")
+	}
+
+	c := a.Clone()
+	a.Remove(1)
+	if !c.ContainsOne(1) || a.ContainsOne(1) {
+		t.Error("A clone should not follow later changes of the original")
+	}
+	if n := a.UnionUpdate(b); n != 1 {
+		t.Errorf("UnionUpdate should add 1 element, added %d", n)
+	}
+	if added, removed := a.SymmetricDifferenceUpdate(a); added != 0 || removed != 3 || !a.IsEmpty() {
+		t.Errorf("SymmetricDifferenceUpdate with itself should empty the set, got %d and %d", added, removed)
+	}
+	if items, n := c.PopN(5); n != 3 || len(items) != 3 || !c.IsEmpty() {
+		t.Errorf("PopN should remove every element, removed %d", n)
+	}
+}
+
+func Test_CopyOnWriteSetSnapshots(t *testing.T) {
+	s := NewCopyOnWriteSet("a", "b")
+	snap := s.load()
+
+	s.Add("c")
+	s.Remove("a")
+	s.Update(func(u Set[string]) {
+		u.Add("d")
+		u.Remove("b")
+	})
+
+	if !snap.Contains("a", "b") || snap.Cardinality() != 2 {
+		t.Error("A published snapshot should never change")
+	}
+	assertEqual(s, NewSet("c", "d"), t)
+
+	before := s.load()
+	s.Add("c")
+	s.Remove("z")
+	if s.load() != before {
+		t.Error("Writes that change nothing should not publish a new snapshot")
+	}
+}
+
+func Test_CopyOnWriteSetJSON(t *testing.T) {
+	s := NewCopyOnWriteSet(1, 2)
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+
+	u := NewCopyOnWriteSet[int]()
+	if err := json.Unmarshal(b, u); err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+	assertEqual[int](u, s, t)
+}
+
+func Test_CopyOnWriteSetConcurrent(t *testing.T) {
+	s := NewCopyOnWriteSet[int]()
+	safe := NewSet[int]()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(2)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				v := g*100 + i
+				s.Update(func(u Set[int]) {
+					u.Add(v)
+					u.Add(-v - 1)
+				})
+				safe.Add(v)
+				if i%10 == 0 {
+					s.UnionUpdate(safe)
+					UnionUpdate(safe, s)
+				}
+			}
+		}(g)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				// Batches are published whole, so readers see as
+				// many negative as non-negative elements.
+				pos, neg := 0, 0
+				s.Each(func(v int) bool {
+					if v < 0 {
+						neg++
+					} else {
+						pos++
+					}
+					return false
+				})
+				if neg > pos {
+					t.Errorf("Saw %d negative elements for %d others", neg, pos)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if s.Cardinality() != 800 {
+		t.Errorf("Expected 800 elements, got %d", s.Cardinality())
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"flag"
+	"fmt"
+)
+
+// FlagValue is a flag.Value that adds the elements of the flag's text to a
+// set. Each occurrence of the flag adds to the set, so --region=us,eu and
+// --region=us --region=eu are equivalent.
+//
+//	regions := mapset.NewSet[string]()
+//	flag.Var(mapset.NewFlagValue(regions, mapset.DefaultTextFormat()), "region", "regions to serve")
+//
+// Elements are parsed as by ParseText: elements implementing
+// encoding.TextUnmarshaler are parsed by it, strings are taken as is and
+// numbers and booleans use their Go syntax. When the format is strict, an
+// element given twice, in one occurrence or in several, is an error.
+type FlagValue[T comparable] struct {
+	set    Set[T]
+	format TextFormat
+}
+
+// Assert interface: FlagValue is a flag.Getter.
+var _ flag.Getter = (*FlagValue[string])(nil)
+
+// NewFlagValue returns a FlagValue adding to s the elements of the flag's
+// text in format f.
+func NewFlagValue[T comparable](s Set[T], f TextFormat) *FlagValue[T] {
+	return &FlagValue[T]{set: s, format: f}
+}
+
+// Set implements flag.Value.
+func (v *FlagValue[T]) Set(text string) error {
+	vals, err := parseText[T](text, v.format)
+	if err != nil {
+		return err
+	}
+	if v.format.Strict {
+		for _, val := range vals {
+			if v.set.ContainsOne(val) {
+				elem, _ := formatElement(val)
+				return fmt.Errorf("mapset: duplicate element %q", elem)
+			}
+		}
+	}
+	v.set.Append(vals...)
+	return nil
+}
+
+// String implements flag.Value, returning the set in the flag's format.
+func (v *FlagValue[T]) String() string {
+	if v == nil || v.set == nil {
+		return ""
+	}
+	text, _ := formatText[T](v.set, v.format)
+	return text
+}
+
+// Get implements flag.Getter, returning the set.
+func (v *FlagValue[T]) Get() any {
+	return v.set
+}
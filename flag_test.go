@@ -0,0 +1,79 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"flag"
+	"io"
+	"testing"
+)
+
+func Test_FlagValue(t *testing.T) {
+	regions := NewSet[string]()
+	ports := NewThreadUnsafeSet[int]()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(NewFlagValue(regions, DefaultTextFormat()), "region", "regions")
+	fs.Var(NewFlagValue(ports, TextFormat{Separator: ":"}), "port", "ports")
+
+	err := fs.Parse([]string{"--region=us,eu", "--region", "ap", "--port=80:443", "--port=80"})
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	assertEqual(regions, NewSet("us", "eu", "ap"), t)
+	assertEqual(ports, NewThreadUnsafeSet(80, 443), t)
+
+	if got := fs.Lookup("region").Value.String(); got != "ap,eu,us" {
+		t.Errorf("Expected ap,eu,us, got %s", got)
+	}
+	if got := fs.Lookup("port").Value.(flag.Getter).Get(); got != ports {
+		t.Errorf("Expected the set, got %v", got)
+	}
+
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse([]string{"--port=http"}); err == nil {
+		t.Error("An invalid element should fail")
+	}
+}
+
+func Test_FlagValueStrict(t *testing.T) {
+	s := NewSet[string]()
+	v := NewFlagValue(s, TextFormat{Strict: true})
+	if err := v.Set("a,b"); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if err := v.Set("a,b,a"); err == nil {
+		t.Error("Duplicates in one occurrence should fail")
+	}
+	if err := v.Set("c,a"); err == nil {
+		t.Error("Duplicates across occurrences should fail")
+	}
+	assertEqual(s, NewSet("a", "b"), t)
+
+	var zero *FlagValue[string]
+	if zero.String() != "" {
+		t.Error("A nil FlagValue should format as an empty string")
+	}
+}
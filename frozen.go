@@ -0,0 +1,146 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+// frozenSet is an immutable snapshot of a set, see Freeze.
+type frozenSet[T comparable] struct {
+	uss *threadUnsafeSet[T]
+}
+
+// Assert concrete type:frozenSet adheres to ReadOnlySet interface.
+var _ ReadOnlySet[string] = (*frozenSet[string])(nil)
+
+// Freeze returns an immutable snapshot of s, to be handed to code that
+// must not be able to modify it. The snapshot has no methods that modify
+// it and can't be converted back to a Set, and since it never changes, it
+// is read without any locking; lookups cost the same as on a thread-unsafe
+// set, even from many goroutines at once. Later changes of s are not
+// reflected in the snapshot.
+//
+// Set algebra on the snapshot returns new thread-safe sets.
+func Freeze[T comparable](s Set[T]) ReadOnlySet[T] {
+	c, unlock := lockOther[T](s)
+	defer unlock()
+
+	uss := newThreadUnsafeSetWithSize[T](c.Cardinality())
+	c.Each(func(elem T) bool {
+		uss.Add(elem)
+		return false
+	})
+	return &frozenSet[T]{uss: uss}
+}
+
+func (f *frozenSet[T]) snapshot() Collection[T] {
+	return f.uss
+}
+
+func (f *frozenSet[T]) Cardinality() int {
+	return len(*f.uss)
+}
+
+func (f *frozenSet[T]) Clone() Set[T] {
+	return &threadSafeSet[T]{uss: f.uss.Clone().(*threadUnsafeSet[T])}
+}
+
+func (f *frozenSet[T]) Contains(v ...T) bool {
+	return f.uss.Contains(v...)
+}
+
+func (f *frozenSet[T]) ContainsOne(v T) bool {
+	return f.uss.ContainsOne(v)
+}
+
+func (f *frozenSet[T]) ContainsAny(v ...T) bool {
+	return f.uss.ContainsAny(v...)
+}
+
+func (f *frozenSet[T]) ContainsAnyElement(other Set[T]) bool {
+	return f.uss.ContainsAnyElement(other)
+}
+
+func (f *frozenSet[T]) Difference(other Set[T]) Set[T] {
+	return &threadSafeSet[T]{uss: f.uss.Difference(other).(*threadUnsafeSet[T])}
+}
+
+func (f *frozenSet[T]) Each(cb func(T) bool) {
+	f.uss.Each(cb)
+}
+
+func (f *frozenSet[T]) Equal(other Set[T]) bool {
+	return f.uss.Equal(other)
+}
+
+func (f *frozenSet[T]) Intersect(other Set[T]) Set[T] {
+	return &threadSafeSet[T]{uss: f.uss.Intersect(other).(*threadUnsafeSet[T])}
+}
+
+func (f *frozenSet[T]) IsEmpty() bool {
+	return len(*f.uss) == 0
+}
+
+func (f *frozenSet[T]) IsProperSubset(other Set[T]) bool {
+	return f.uss.IsProperSubset(other)
+}
+
+func (f *frozenSet[T]) IsProperSuperset(other Set[T]) bool {
+	return f.uss.IsProperSuperset(other)
+}
+
+func (f *frozenSet[T]) IsSubset(other Set[T]) bool {
+	return f.uss.IsSubset(other)
+}
+
+func (f *frozenSet[T]) IsSuperset(other Set[T]) bool {
+	return f.uss.IsSuperset(other)
+}
+
+func (f *frozenSet[T]) Iter() <-chan T {
+	return f.uss.Iter()
+}
+
+func (f *frozenSet[T]) Iterator() *Iterator[T] {
+	return f.uss.Iterator()
+}
+
+func (f *frozenSet[T]) String() string {
+	return f.uss.String()
+}
+
+func (f *frozenSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	return &threadSafeSet[T]{uss: f.uss.SymmetricDifference(other).(*threadUnsafeSet[T])}
+}
+
+func (f *frozenSet[T]) ToSlice() []T {
+	return f.uss.ToSlice()
+}
+
+func (f *frozenSet[T]) Union(other Set[T]) Set[T] {
+	return &threadSafeSet[T]{uss: f.uss.Union(other).(*threadUnsafeSet[T])}
+}
+
+func (f *frozenSet[T]) MarshalJSON() ([]byte, error) {
+	return f.uss.MarshalJSON()
+}
@@ -0,0 +1,104 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+)
+
+func Test_Freeze(t *testing.T) {
+	test := func(t *testing.T, s Set[int]) {
+		s.Append(1, 2, 3)
+		f := Freeze(s)
+		s.Add(4)
+
+		if f.Cardinality() != 3 || f.ContainsOne(4) || !f.Contains(1, 2, 3) {
+			t.Errorf("A frozen set should not follow later changes, got %v", f)
+		}
+		if _, ok := f.(Set[int]); ok {
+			t.Error("A frozen set should not be convertible to a Set")
+		}
+
+		assertEqual(f.Union(NewSet(5)), NewSet(1, 2, 3, 5), t)
+		assertEqual(f.Difference(s), NewSet[int](), t)
+		if _, ok := f.Union(s).(*threadSafeSet[int]); !ok {
+			t.Error("Set algebra on a frozen set should return thread-safe sets")
+		}
+		if !f.IsProperSubset(s) || !f.ContainsAnyElement(s) || f.IsSuperset(s) {
+			t.Error("Frozen sets should be comparable with the other sets")
+		}
+		if n := DifferenceUpdate(s, f.Clone()); n != 3 {
+			t.Errorf("DifferenceUpdate should remove 3 elements, removed %d", n)
+		}
+
+		c := f.Clone()
+		c.Add(10)
+		if f.ContainsOne(10) {
+			t.Error("Modifying a clone should not modify the frozen set")
+		}
+
+		b, err := json.Marshal(f)
+		if err != nil {
+			t.Errorf("Error should be nil: %v", err)
+		}
+		u := NewSet[int]()
+		if err := json.Unmarshal(b, u); err != nil {
+			t.Errorf("Error should be nil: %v", err)
+		}
+		if !f.Equal(u) {
+			t.Errorf("Expected %v, got %v", f, u)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) { test(t, NewSet[int]()) })
+	t.Run("Unsafe", func(t *testing.T) { test(t, NewThreadUnsafeSet[int]()) })
+}
+
+func Test_FreezeConcurrentReads(t *testing.T) {
+	s := NewSet[int]()
+	for i := 0; i < 100; i++ {
+		s.Add(i)
+	}
+	f := Freeze(s)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if !f.ContainsOne(i) {
+					t.Errorf("Element %d should be found", i)
+				}
+				s.Remove(i)
+				s.Append(f.ToSlice()...)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,150 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+// Filter returns a new set containing the elements of s for which pred
+// returns true. The returned set uses the same implementation as s.
+func Filter[T comparable](s ReadOnlySet[T], pred func(T) bool) Set[T] {
+	c, unlock := lockOther[T](s)
+	defer unlock()
+
+	ret := newSetLike[T](s, 0)
+	dst := unshared(ret)
+	c.Each(func(elem T) bool {
+		if pred(elem) {
+			dst.Add(elem)
+		}
+		return false
+	})
+	return ret
+}
+
+// Partition splits s into two new sets: the elements for which pred returns
+// true and the elements for which it returns false. Both sets use the same
+// implementation as s.
+func Partition[T comparable](s ReadOnlySet[T], pred func(T) bool) (matched, unmatched Set[T]) {
+	c, unlock := lockOther[T](s)
+	defer unlock()
+
+	matched, unmatched = newSetLike[T](s, 0), newSetLike[T](s, 0)
+	yes, no := unshared(matched), unshared(unmatched)
+	c.Each(func(elem T) bool {
+		if pred(elem) {
+			yes.Add(elem)
+		} else {
+			no.Add(elem)
+		}
+		return false
+	})
+	return matched, unmatched
+}
+
+// GroupBy splits s into new sets of the elements sharing the same key, as
+// computed by keyFn, and returns them indexed by that key. Every set uses
+// the same implementation as s. Grouping the empty set returns an empty map.
+func GroupBy[T, K comparable](s ReadOnlySet[T], keyFn func(T) K) map[K]Set[T] {
+	c, unlock := lockOther[T](s)
+	defer unlock()
+
+	groups := make(map[K]Set[T])
+	building := make(map[K]Set[T])
+	c.Each(func(elem T) bool {
+		k := keyFn(elem)
+		dst, ok := building[k]
+		if !ok {
+			group := newSetLike[T](s, 0)
+			groups[k] = group
+			dst = unshared(group)
+			building[k] = dst
+		}
+		dst.Add(elem)
+		return false
+	})
+	return groups
+}
+
+// Map returns a new set containing the result of f for every element of s.
+// As f may map several elements to the same value, the returned set can be
+// smaller than s. The returned set uses the same implementation as s when
+// this package can build one holding elements of type U, and is otherwise
+// the default thread-safe set. Sets tied to their element type, such as a
+// SortedSet, are only kept when U is T.
+func Map[T, U comparable](s ReadOnlySet[T], f func(T) U) Set[U] {
+	c, unlock := lockOther[T](s)
+	defer unlock()
+
+	ret := newSetLike[U](s, c.Cardinality())
+	dst := unshared(ret)
+	c.Each(func(elem T) bool {
+		dst.Add(f(elem))
+		return false
+	})
+	return ret
+}
+
+// Reduce folds the elements of s into a single value, calling f with the
+// accumulated value and each element in turn, starting from initial. As
+// sets are unordered, f should not depend on the order of the elements.
+func Reduce[T comparable, A any](s ReadOnlySet[T], initial A, f func(A, T) A) A {
+	c, unlock := lockOther[T](s)
+	defer unlock()
+
+	acc := initial
+	c.Each(func(elem T) bool {
+		acc = f(acc, elem)
+		return false
+	})
+	return acc
+}
+
+// Any returns whether pred returns true for at least one element of s.
+// It stops at the first such element and returns false for the empty set.
+func Any[T comparable](s ReadOnlySet[T], pred func(T) bool) bool {
+	c, unlock := lockOther[T](s)
+	defer unlock()
+
+	found := false
+	c.Each(func(elem T) bool {
+		found = pred(elem)
+		return found
+	})
+	return found
+}
+
+// All returns whether pred returns true for every element of s. It stops
+// at the first element for which pred returns false and returns true for
+// the empty set.
+func All[T comparable](s ReadOnlySet[T], pred func(T) bool) bool {
+	c, unlock := lockOther[T](s)
+	defer unlock()
+
+	all := true
+	c.Each(func(elem T) bool {
+		all = pred(elem)
+		return !all
+	})
+	return all
+}
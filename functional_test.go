@@ -0,0 +1,230 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"strconv"
+	"testing"
+)
+
+func Test_Filter(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		s := ctor(1, 2, 3, 4, 5, 6)
+		even := Filter[int](s, func(v int) bool { return v%2 == 0 })
+
+		assertEqual(even, NewSet(2, 4, 6), t)
+		if isThreadUnsafe(even) != isThreadUnsafe(s) {
+			t.Error("Filter should return a set of the same implementation as its input")
+		}
+		if s.Cardinality() != 6 {
+			t.Error("Filter should not modify its input")
+		}
+		if !Filter[int](s, func(int) bool { return false }).IsEmpty() {
+			t.Error("Filter rejecting everything should return the empty set")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_Map(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		s := ctor(1, 2, 3, 11, 12)
+		strs := Map[int, string](s, strconv.Itoa)
+		assertEqual(strs, NewSet("1", "2", "3", "11", "12"), t)
+
+		_, unsafe := strs.(*threadUnsafeSet[string])
+		if unsafe != isThreadUnsafe(s) {
+			t.Error("Map should return a set of the same implementation as its input")
+		}
+
+		mod := Map[int, int](s, func(v int) int { return v % 10 })
+		assertEqual(mod, NewSet(1, 2, 3), t)
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+
+	bits := NewBitSet[uint](1, 2)
+	if _, ok := Map[uint](bits, func(v uint) uint { return v * 2 }).(*BitSet[uint]); !ok {
+		t.Error("Map should keep a BitSet when the element type does not change")
+	}
+	if _, ok := Map[uint](bits, func(v uint) int { return int(v) }).(*threadSafeSet[int]); !ok {
+		t.Error("Map should fall back to a thread-safe set for a BitSet of another element type")
+	}
+}
+
+func Test_Partition(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		s := ctor(1, 2, 3, 4, 5)
+		even, odd := Partition[int](s, func(v int) bool { return v%2 == 0 })
+
+		assertEqual(even, NewSet(2, 4), t)
+		assertEqual(odd, NewSet(1, 3, 5), t)
+		if isThreadUnsafe(even) != isThreadUnsafe(s) || isThreadUnsafe(odd) != isThreadUnsafe(s) {
+			t.Error("Partition should return sets of the same implementation as its input")
+		}
+
+		all, none := Partition[int](s, func(int) bool { return true })
+		assertEqual(all, s, t)
+		if !none.IsEmpty() {
+			t.Error("Partition matching everything should leave the second set empty")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_GroupBy(t *testing.T) {
+	type user struct {
+		tenant string
+		name   string
+	}
+
+	test := func(t *testing.T, ctor func(vals ...user) Set[user]) {
+		s := ctor(user{"acme", "ann"}, user{"acme", "bob"}, user{"initech", "peter"})
+		groups := GroupBy[user, string](s, func(u user) string { return u.tenant })
+
+		if len(groups) != 2 {
+			t.Fatalf("Expected 2 groups, got %d", len(groups))
+		}
+		assertEqual(groups["acme"], NewSet(user{"acme", "ann"}, user{"acme", "bob"}), t)
+		assertEqual(groups["initech"], NewSet(user{"initech", "peter"}), t)
+		for _, group := range groups {
+			if isThreadUnsafe(group) != isThreadUnsafe(s) {
+				t.Error("GroupBy should return sets of the same implementation as its input")
+			}
+		}
+
+		if len(GroupBy[user, string](ctor(), func(u user) string { return u.tenant })) != 0 {
+			t.Error("Grouping the empty set should return an empty map")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[user])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[user])
+	})
+}
+
+func Test_Reduce(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		sum := Reduce[int, int](ctor(1, 2, 3, 4), 0, func(acc, v int) int { return acc + v })
+		if sum != 10 {
+			t.Errorf("Expected the sum of {1, 2, 3, 4} to be 10, got %d", sum)
+		}
+
+		ret := Reduce[int, string](ctor(), "empty", func(acc string, v int) string { return "not empty" })
+		if ret != "empty" {
+			t.Errorf("Reducing the empty set should return the initial value, got %q", ret)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_AnyAll(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		s := ctor(2, 4, 5, 6)
+		isEven := func(v int) bool { return v%2 == 0 }
+		isPositive := func(v int) bool { return v > 0 }
+
+		if !Any[int](s, isEven) || Any[int](s, func(v int) bool { return v > 10 }) {
+			t.Error("Any should report whether one element matches")
+		}
+		if All[int](s, isEven) || !All[int](s, isPositive) {
+			t.Error("All should report whether every element matches")
+		}
+		if Any[int](ctor(), isPositive) || !All[int](ctor(), isEven) {
+			t.Error("Any should be false and All should be true for the empty set")
+		}
+
+		calls := 0
+		Any[int](s, func(int) bool {
+			calls++
+			return true
+		})
+		if calls != 1 {
+			t.Errorf("Any should stop at the first match, called %d times", calls)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
+func Test_FunctionalReadOnly(t *testing.T) {
+	s := NewThreadUnsafeSet(1, 2, 3, 4)
+	f := Freeze(s)
+	isEven := func(v int) bool { return v%2 == 0 }
+
+	even := Filter(f, isEven)
+	assertEqual(even, NewSet(2, 4), t)
+	if _, ok := even.(*threadSafeSet[int]); !ok {
+		t.Errorf("Filter over a frozen set should return a thread-safe set, got %T", even)
+	}
+	matched, unmatched := Partition(f, isEven)
+	assertEqual(matched, NewSet(2, 4), t)
+	assertEqual(unmatched, NewSet(1, 3), t)
+	if groups := GroupBy(f, isEven); len(groups) != 2 || !groups[false].Equal(NewSet(1, 3)) {
+		t.Errorf("Unexpected groups %v", groups)
+	}
+	assertEqual(Map(f, strconv.Itoa), NewSet("1", "2", "3", "4"), t)
+	if sum := Reduce(f, 0, func(acc, v int) int { return acc + v }); sum != 10 {
+		t.Errorf("Expected a sum of 10, got %d", sum)
+	}
+	if !Any(f, isEven) || All(f, isEven) {
+		t.Error("Any and All should read a frozen set")
+	}
+	if s.Cardinality() != 4 {
+		t.Error("The functions should not modify the frozen set")
+	}
+}
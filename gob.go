@@ -0,0 +1,57 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import "encoding/gob"
+
+// RegisterGob registers the set implementations holding elements of type T
+// with encoding/gob, which is needed to gob-encode a set stored in an
+// interface-typed field, such as a Set[T] field of a struct. Sets of the
+// basic types listed in this file are registered already. It is safe to
+// call RegisterGob several times for the same type.
+func RegisterGob[T comparable]() {
+	gob.Register(newThreadSafeSet[T]())
+	gob.Register(newThreadUnsafeSet[T]())
+	gob.Register(newThreadSafeOrderedSet[T](0))
+	gob.Register(newOrderedSet[T](0))
+}
+
+func init() {
+	RegisterGob[string]()
+	RegisterGob[int]()
+	RegisterGob[int8]()
+	RegisterGob[int16]()
+	RegisterGob[int32]()
+	RegisterGob[int64]()
+	RegisterGob[uint]()
+	RegisterGob[uint8]()
+	RegisterGob[uint16]()
+	RegisterGob[uint32]()
+	RegisterGob[uint64]()
+	RegisterGob[float32]()
+	RegisterGob[float64]()
+	RegisterGob[bool]()
+}
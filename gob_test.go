@@ -0,0 +1,140 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"testing"
+)
+
+type gobPoint struct {
+	X, Y int
+}
+
+type gobRecord struct {
+	Name   string
+	Tags   Set[string]
+	IDs    Set[int]
+	Points Set[gobPoint]
+}
+
+func Test_GobEncodeDecode(t *testing.T) {
+	test := func(t *testing.T, s, u Set[string]) {
+		s.Append("a", "b", "c")
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+
+		u.Add("z")
+		if err := gob.NewDecoder(&buf).Decode(u); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		assertEqual(u, s, t)
+	}
+
+	t.Run("Safe", func(t *testing.T) { test(t, NewSet[string](), NewSet[string]()) })
+	t.Run("Unsafe", func(t *testing.T) { test(t, NewThreadUnsafeSet[string](), NewThreadUnsafeSet[string]()) })
+}
+
+func Test_GobInterfaceFields(t *testing.T) {
+	RegisterGob[gobPoint]()
+	RegisterGob[gobPoint]()
+
+	in := gobRecord{
+		Name:   "record",
+		Tags:   NewSet("x", "y"),
+		IDs:    NewThreadUnsafeSet[int](),
+		Points: NewSet(gobPoint{1, 2}, gobPoint{3, 4}),
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+
+	var out gobRecord
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if out.Name != "record" {
+		t.Errorf("Expected name record, got %s", out.Name)
+	}
+	assertEqual(out.Tags, in.Tags, t)
+	assertEqual(out.Points, in.Points, t)
+	if out.IDs == nil || !out.IDs.IsEmpty() {
+		t.Errorf("An empty set should decode as an empty set, got %v", out.IDs)
+	}
+	if _, ok := out.Tags.(*threadSafeSet[string]); !ok {
+		t.Error("A thread-safe set should decode as a thread-safe set")
+	}
+	if _, ok := out.IDs.(*threadUnsafeSet[int]); !ok {
+		t.Error("A thread-unsafe set should decode as a thread-unsafe set")
+	}
+
+	out.Tags.Add("z")
+	if in.Tags.ContainsOne("z") {
+		t.Error("A decoded set should not share state with the original")
+	}
+}
+
+func Test_GobOrdered(t *testing.T) {
+	test := func(t *testing.T, s, u Set[string]) {
+		s.Append("c", "a", "b")
+		in := gobRecord{Name: "ordered", Tags: s}
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+
+		var out gobRecord
+		if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		if got := out.Tags.ToSlice(); len(got) != 3 || got[0] != "c" || got[1] != "a" || got[2] != "b" {
+			t.Errorf("Expected [c a b], got %v", got)
+		}
+		if fmt.Sprintf("%T", out.Tags) != fmt.Sprintf("%T", s) {
+			t.Errorf("Expected a %T, got %T", s, out.Tags)
+		}
+
+		u.Add("z")
+		buf.Reset()
+		if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		if err := gob.NewDecoder(&buf).Decode(u); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		if got := u.ToSlice(); len(got) != 3 || got[0] != "c" || got[2] != "b" {
+			t.Errorf("Decoding should replace the contents in order, got %v", got)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) { test(t, NewOrderedSet[string](), NewOrderedSet[string]()) })
+	t.Run("Unsafe", func(t *testing.T) { test(t, NewThreadUnsafeOrderedSet[string](), NewThreadUnsafeOrderedSet[string]()) })
+}
@@ -0,0 +1,89 @@
+//go:build !go1.24
+// +build !go1.24
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/maphash"
+	"math"
+	"reflect"
+)
+
+// hashComparable returns the hash ImmutableSet and sharded sets use for v.
+// Before go1.24 the standard library cannot hash an arbitrary comparable
+// value, so common types are hashed directly, pointers and channels by
+// their address, and any other value through its %#v representation.
+// Equal values format identically, with the exception of composite values
+// holding floating-point negative zero, which therefore should not be
+// stored in these sets on older toolchains.
+func hashComparable[T comparable](v T) uint64 {
+	var h maphash.Hash
+	h.SetSeed(hashSeed)
+
+	var buf [8]byte
+	switch x := any(v).(type) {
+	case string:
+		h.WriteString(x)
+		return h.Sum64()
+	case int:
+		binary.LittleEndian.PutUint64(buf[:], uint64(x))
+	case int64:
+		binary.LittleEndian.PutUint64(buf[:], uint64(x))
+	case int32:
+		binary.LittleEndian.PutUint64(buf[:], uint64(x))
+	case uint:
+		binary.LittleEndian.PutUint64(buf[:], uint64(x))
+	case uint64:
+		binary.LittleEndian.PutUint64(buf[:], x)
+	case uint32:
+		binary.LittleEndian.PutUint64(buf[:], uint64(x))
+	case float64:
+		if x == 0 {
+			x = 0 // negative zero equals zero
+		}
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(x))
+	case float32:
+		if x == 0 {
+			x = 0 // negative zero equals zero
+		}
+		binary.LittleEndian.PutUint64(buf[:], uint64(math.Float32bits(x)))
+	default:
+		switch rv := reflect.ValueOf(v); rv.Kind() {
+		case reflect.Ptr, reflect.Chan, reflect.UnsafePointer:
+			// %#v would format what a pointer points to, which can change.
+			binary.LittleEndian.PutUint64(buf[:], uint64(rv.Pointer()))
+		default:
+			fmt.Fprintf(&h, "%#v", v)
+			return h.Sum64()
+		}
+	}
+	h.Write(buf[:])
+	return h.Sum64()
+}
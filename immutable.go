@@ -0,0 +1,396 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+	"hash/maphash"
+	"math/bits"
+)
+
+const (
+	// hamtBits is the number of hash bits consumed at each level of the
+	// trie, so that every node has up to 32 slots.
+	hamtBits = 5
+	hamtMask = 1<<hamtBits - 1
+	// hamtMaxShift is the shift past which every hash bit has been used;
+	// the nodes found there hold colliding elements in a plain list.
+	hamtMaxShift = 64
+)
+
+// hashSeed seeds the hashes of every ImmutableSet in the process.
+var hashSeed = maphash.MakeSeed()
+
+// ImmutableSet is a persistent set based on a hash array mapped trie. It is
+// never modified once built: With and Without return new versions of the
+// set, which share all of their unchanged structure with the original, so
+// that taking a snapshot costs nothing and deriving a new version only
+// copies the O(log n) nodes leading to the changed element.
+//
+// Since no version ever changes, an ImmutableSet can be read from any
+// number of goroutines without locking. The zero value is an empty set
+// ready to use.
+type ImmutableSet[T comparable] struct {
+	root *hamtNode[T]
+	size int
+}
+
+// Assert concrete type:ImmutableSet can be read by the set operations.
+var _ Collection[string] = ImmutableSet[string]{}
+
+// hamtNode is a trie node. bitmap records which of the 32 slots for the
+// node's 5 bits of hash are in use, and entries holds the used slots in
+// order. Nodes past hamtMaxShift have no bitmap and list their entries.
+type hamtNode[T comparable] struct {
+	bitmap  uint32
+	entries []hamtEntry[T]
+}
+
+// hamtEntry is either a single element with its hash, or a child node.
+type hamtEntry[T comparable] struct {
+	hash  uint64
+	value T
+	child *hamtNode[T]
+}
+
+// NewImmutableSet creates and returns a new immutable set with the given
+// elements.
+func NewImmutableSet[T comparable](vals ...T) ImmutableSet[T] {
+	var s ImmutableSet[T]
+	for _, v := range vals {
+		s.insertOwned(v)
+	}
+	return s
+}
+
+// NewImmutableSetFrom creates and returns an immutable set holding the
+// elements of s. A thread-safe s is read under a single read lock, so the
+// result is a consistent snapshot.
+func NewImmutableSetFrom[T comparable](s Collection[T]) ImmutableSet[T] {
+	c, unlock := lockOther[T](s)
+	defer unlock()
+
+	var r ImmutableSet[T]
+	c.Each(func(v T) bool {
+		r.insertOwned(v)
+		return false
+	})
+	return r
+}
+
+// insertOwned adds v to s in place. It may only be called while s is
+// being built, before any other version can share its nodes.
+func (s *ImmutableSet[T]) insertOwned(v T) {
+	if s.root == nil {
+		s.root = &hamtNode[T]{}
+	}
+	if _, added := s.root.with(hamtEntry[T]{hash: hashComparable(v), value: v}, 0, true); added {
+		s.size++
+	}
+}
+
+// slot returns the bit of the slot for hash at shift, and the position of
+// that slot in n.entries.
+func (n *hamtNode[T]) slot(hash uint64, shift uint) (uint32, int) {
+	bit := uint32(1) << (hash >> shift & hamtMask)
+	return bit, bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+// copyFor returns n itself when it may be modified in place, or a copy.
+func (n *hamtNode[T]) copyFor(inPlace bool) *hamtNode[T] {
+	if inPlace {
+		return n
+	}
+	entries := make([]hamtEntry[T], len(n.entries), len(n.entries)+1)
+	copy(entries, n.entries)
+	return &hamtNode[T]{bitmap: n.bitmap, entries: entries}
+}
+
+func (n *hamtNode[T]) contains(hash uint64, v T, shift uint) bool {
+	for ; shift < hamtMaxShift; shift += hamtBits {
+		bit, pos := n.slot(hash, shift)
+		if n.bitmap&bit == 0 {
+			return false
+		}
+		e := n.entries[pos]
+		if e.child == nil {
+			return e.hash == hash && e.value == v
+		}
+		n = e.child
+	}
+	for _, e := range n.entries {
+		if e.value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// with returns n with the element e added, and whether it was missing.
+// Unless inPlace is set, n is left untouched and the nodes on the path to
+// e are copied.
+func (n *hamtNode[T]) with(e hamtEntry[T], shift uint, inPlace bool) (*hamtNode[T], bool) {
+	if shift >= hamtMaxShift {
+		for _, x := range n.entries {
+			if x.value == e.value {
+				return n, false
+			}
+		}
+		c := n.copyFor(inPlace)
+		c.entries = append(c.entries, e)
+		return c, true
+	}
+
+	bit, pos := n.slot(e.hash, shift)
+	if n.bitmap&bit == 0 {
+		c := n.copyFor(inPlace)
+		c.bitmap |= bit
+		c.entries = append(c.entries, hamtEntry[T]{})
+		copy(c.entries[pos+1:], c.entries[pos:])
+		c.entries[pos] = e
+		return c, true
+	}
+
+	var child *hamtNode[T]
+	switch x := n.entries[pos]; {
+	case x.child != nil:
+		var added bool
+		if child, added = x.child.with(e, shift+hamtBits, inPlace); !added {
+			return n, false
+		}
+	case x.hash == e.hash && x.value == e.value:
+		return n, false
+	default:
+		child = newHamtPair(x, e, shift+hamtBits)
+	}
+	c := n.copyFor(inPlace)
+	c.entries[pos] = hamtEntry[T]{child: child}
+	return c, true
+}
+
+// newHamtPair returns a node at shift holding the two elements a and b.
+func newHamtPair[T comparable](a, b hamtEntry[T], shift uint) *hamtNode[T] {
+	if shift >= hamtMaxShift {
+		return &hamtNode[T]{entries: []hamtEntry[T]{a, b}}
+	}
+	ia, ib := a.hash>>shift&hamtMask, b.hash>>shift&hamtMask
+	if ia == ib {
+		return &hamtNode[T]{
+			bitmap:  1 << ia,
+			entries: []hamtEntry[T]{{child: newHamtPair(a, b, shift+hamtBits)}},
+		}
+	}
+	if ia > ib {
+		a, b = b, a
+	}
+	return &hamtNode[T]{bitmap: 1<<ia | 1<<ib, entries: []hamtEntry[T]{a, b}}
+}
+
+// without returns n with v removed, and whether it was present. n is left
+// untouched. The result is nil when n held v alone.
+func (n *hamtNode[T]) without(hash uint64, v T, shift uint) (*hamtNode[T], bool) {
+	if shift >= hamtMaxShift {
+		for i, x := range n.entries {
+			if x.value == v {
+				return n.withoutEntry(i, 0), true
+			}
+		}
+		return n, false
+	}
+
+	bit, pos := n.slot(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	x := n.entries[pos]
+	if x.child == nil {
+		if x.hash != hash || x.value != v {
+			return n, false
+		}
+		return n.withoutEntry(pos, bit), true
+	}
+
+	child, removed := x.child.without(hash, v, shift+hamtBits)
+	switch {
+	case !removed:
+		return n, false
+	case child == nil:
+		return n.withoutEntry(pos, bit), true
+	}
+	c := n.copyFor(false)
+	if len(child.entries) == 1 && child.entries[0].child == nil {
+		// Pull a lone element up, so that the trie stays as shallow
+		// as it would have been had the removed element never been
+		// added.
+		c.entries[pos] = child.entries[0]
+	} else {
+		c.entries[pos] = hamtEntry[T]{child: child}
+	}
+	return c, true
+}
+
+// withoutEntry returns a copy of n without the entry at pos, whose slot
+// bit is bit, or nil if that was its only entry.
+func (n *hamtNode[T]) withoutEntry(pos int, bit uint32) *hamtNode[T] {
+	if len(n.entries) == 1 {
+		return nil
+	}
+	entries := make([]hamtEntry[T], 0, len(n.entries)-1)
+	entries = append(append(entries, n.entries[:pos]...), n.entries[pos+1:]...)
+	return &hamtNode[T]{bitmap: n.bitmap &^ bit, entries: entries}
+}
+
+// each calls cb on every element below n and reports whether cb stopped
+// the iteration.
+func (n *hamtNode[T]) each(cb func(T) bool) bool {
+	for _, e := range n.entries {
+		if e.child != nil {
+			if e.child.each(cb) {
+				return true
+			}
+		} else if cb(e.value) {
+			return true
+		}
+	}
+	return false
+}
+
+// With returns a version of the set that also contains v. The receiver is
+// left unchanged.
+func (s ImmutableSet[T]) With(v T) ImmutableSet[T] {
+	root := s.root
+	if root == nil {
+		root = &hamtNode[T]{}
+	}
+	root, added := root.with(hamtEntry[T]{hash: hashComparable(v), value: v}, 0, false)
+	if !added {
+		return s
+	}
+	return ImmutableSet[T]{root: root, size: s.size + 1}
+}
+
+// Without returns a version of the set that does not contain v. The
+// receiver is left unchanged.
+func (s ImmutableSet[T]) Without(v T) ImmutableSet[T] {
+	if s.root == nil {
+		return s
+	}
+	root, removed := s.root.without(hashComparable(v), v, 0)
+	if !removed {
+		return s
+	}
+	return ImmutableSet[T]{root: root, size: s.size - 1}
+}
+
+// Cardinality returns the number of elements in the set.
+func (s ImmutableSet[T]) Cardinality() int {
+	return s.size
+}
+
+// Contains returns whether the given items are all in the set.
+func (s ImmutableSet[T]) Contains(v ...T) bool {
+	for _, val := range v {
+		if !s.ContainsOne(val) {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsOne returns whether the given item is in the set.
+func (s ImmutableSet[T]) ContainsOne(v T) bool {
+	return s.root != nil && s.root.contains(hashComparable(v), v, 0)
+}
+
+// Each iterates over elements and executes the passed func against each
+// element. If passed func returns true, stop iteration at the time.
+func (s ImmutableSet[T]) Each(cb func(T) bool) {
+	if s.root != nil {
+		s.root.each(cb)
+	}
+}
+
+// Equal determines if two immutable sets hold the same elements. Versions
+// derived from one another compare in time proportional to the elements
+// they don't share.
+func (s ImmutableSet[T]) Equal(other ImmutableSet[T]) bool {
+	if s.size != other.size {
+		return false
+	}
+	if s.root == other.root {
+		return true
+	}
+	return isSubsetOf[T](s, other)
+}
+
+// IsEmpty determines if there are elements in the set.
+func (s ImmutableSet[T]) IsEmpty() bool {
+	return s.size == 0
+}
+
+// String provides a convenient string representation of the set.
+func (s ImmutableSet[T]) String() string {
+	return formatCollection[T](s)
+}
+
+// ToSet returns a new thread-safe Set holding the elements of s.
+func (s ImmutableSet[T]) ToSet() Set[T] {
+	r := newThreadSafeSetWithSize[T](s.size)
+	s.Each(func(v T) bool {
+		r.uss.Add(v)
+		return false
+	})
+	return r
+}
+
+// ToSlice returns the members of the set as a slice.
+func (s ImmutableSet[T]) ToSlice() []T {
+	return collectionToSlice[T](s)
+}
+
+// MarshalJSON creates a JSON array from the set, it marshals all elements
+func (s ImmutableSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalCollectionJSON[T](s)
+}
+
+// UnmarshalJSON makes s a version of the set that also contains the
+// elements of a JSON array. Other versions sharing its structure are left
+// unchanged.
+func (s *ImmutableSet[T]) UnmarshalJSON(b []byte) error {
+	var i []T
+	err := json.Unmarshal(b, &i)
+	if err != nil {
+		return err
+	}
+	if s.root == nil {
+		*s = NewImmutableSet(i...)
+		return nil
+	}
+	for _, v := range i {
+		*s = s.With(v)
+	}
+	return nil
+}
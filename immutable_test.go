@@ -0,0 +1,226 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func Test_ImmutableSetVersions(t *testing.T) {
+	var empty ImmutableSet[string]
+	if !empty.IsEmpty() || empty.ContainsOne("a") || empty.Without("a").Cardinality() != 0 {
+		t.Error("The zero ImmutableSet should be empty")
+	}
+
+	v1 := empty.With("a").With("b")
+	v2 := v1.With("c").Without("a")
+	v3 := v2.With("c")
+
+	if !v1.Contains("a", "b") || v1.ContainsOne("c") || v1.Cardinality() != 2 {
+		t.Errorf("Deriving new versions should leave v1 unchanged, got %v", v1)
+	}
+	if !v2.Contains("b", "c") || v2.ContainsOne("a") || v2.Cardinality() != 2 {
+		t.Errorf("Unexpected v2: %v", v2)
+	}
+	if v3.root != v2.root {
+		t.Error("Adding an existing element should return the same version")
+	}
+	if !empty.IsEmpty() {
+		t.Error("Deriving new versions should leave the empty set unchanged")
+	}
+	if !v2.Equal(NewImmutableSet("c", "b")) || v1.Equal(v2) {
+		t.Error("Equal should compare elements")
+	}
+}
+
+func Test_ImmutableSetMatchesMapSet(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	s := NewImmutableSet[int]()
+	m := NewThreadUnsafeSet[int]()
+	var versions []ImmutableSet[int]
+	var snapshots []Set[int]
+
+	for i := 0; i < 20000; i++ {
+		v := r.Intn(5000)
+		if r.Intn(3) == 0 {
+			s = s.Without(v)
+			m.Remove(v)
+		} else {
+			s = s.With(v)
+			m.Add(v)
+		}
+		if i%1000 == 0 {
+			versions = append(versions, s)
+			snapshots = append(snapshots, m.Clone())
+		}
+	}
+
+	if s.Cardinality() != m.Cardinality() || !m.Equal(NewImmutableSetFrom[int](m).ToSet()) {
+		t.Fatalf("Expected %d elements, got %d", m.Cardinality(), s.Cardinality())
+	}
+	for i, v := range versions {
+		if v.Cardinality() != snapshots[i].Cardinality() || !snapshots[i].Equal(v.ToSet()) {
+			t.Fatalf("Version %d should be unchanged by later versions", i)
+		}
+	}
+}
+
+func Test_ImmutableSetCollisions(t *testing.T) {
+	// Give every element the same hash, so that they all end up in one
+	// list past the last level of the trie.
+	root := &hamtNode[int]{}
+	for v := 0; v < 10; v++ {
+		var added bool
+		if root, added = root.with(hamtEntry[int]{hash: 42, value: v}, 0, false); !added {
+			t.Errorf("Element %d should have been added", v)
+		}
+	}
+	if _, added := root.with(hamtEntry[int]{hash: 42, value: 3}, 0, false); added {
+		t.Error("A colliding element should not be added twice")
+	}
+	for v := 0; v < 10; v++ {
+		if !root.contains(42, v, 0) {
+			t.Errorf("Element %d should be found", v)
+		}
+	}
+	if root.contains(42, 10, 0) || root.contains(43, 1, 0) {
+		t.Error("Missing elements should not be found")
+	}
+
+	for v := 0; v < 9; v++ {
+		var removed bool
+		if root, removed = root.without(42, v, 0); !removed {
+			t.Errorf("Element %d should have been removed", v)
+		}
+	}
+	if len(root.entries) != 1 || root.entries[0].child != nil || root.entries[0].value != 9 {
+		t.Error("The last colliding element should be pulled up to the root")
+	}
+	if root, _ = root.without(42, 9, 0); root != nil {
+		t.Error("Removing every element should leave no node")
+	}
+}
+
+func Test_ImmutableSetConversions(t *testing.T) {
+	s := NewSet("a", "b", "c")
+	im := NewImmutableSetFrom[string](s)
+	s.Add("d")
+	if im.ContainsOne("d") || im.Cardinality() != 3 {
+		t.Error("An ImmutableSet should not follow later changes of its source")
+	}
+	assertEqual(im.ToSet(), NewSet("a", "b", "c"), t)
+
+	got := im.ToSlice()
+	sort.Strings(got)
+	if len(got) != 3 || got[0] != "a" || got[2] != "c" {
+		t.Errorf("Unexpected ToSlice: %v", got)
+	}
+	if !s.IsSuperset(im.ToSet()) {
+		t.Error("ToSet should be usable with the other set operations")
+	}
+}
+
+func Test_ImmutableSetPointers(t *testing.T) {
+	type counter struct{ n int }
+	ptrs := make([]*counter, 100)
+	for i := range ptrs {
+		ptrs[i] = &counter{n: i}
+	}
+	im := NewImmutableSet(ptrs...)
+
+	for _, p := range ptrs {
+		p.n += 1000
+	}
+	for i, p := range ptrs {
+		if !im.ContainsOne(p) {
+			t.Errorf("Pointer %d should be found after its pointee changed", i)
+		}
+	}
+	if im.ContainsOne(&counter{n: 1000}) {
+		t.Error("A pointer to an equal value should not be found")
+	}
+	if im = im.Without(ptrs[0]); im.Cardinality() != 99 || im.ContainsOne(ptrs[0]) {
+		t.Error("Without should remove a pointer whose pointee changed")
+	}
+}
+
+func Test_ImmutableSetJSON(t *testing.T) {
+	s := NewImmutableSet(1, 2, 3)
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+
+	var u ImmutableSet[int]
+	if err := json.Unmarshal(b, &u); err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+	if !u.Equal(s) {
+		t.Errorf("Expected %v, got %v", s, u)
+	}
+
+	v := u
+	if err := json.Unmarshal([]byte("[4]"), &v); err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+	if !v.Contains(1, 4) || u.ContainsOne(4) {
+		t.Error("UnmarshalJSON should add to a new version only")
+	}
+}
+
+func Test_ImmutableSetConcurrentReads(t *testing.T) {
+	s := NewImmutableSet[int]()
+	for i := 0; i < 1000; i++ {
+		s = s.With(i)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			local := s
+			for i := 0; i < 1000; i++ {
+				if !s.ContainsOne(i) {
+					t.Errorf("Element %d should be found", i)
+				}
+				local = local.Without(i).With(i + 1000*(g+1))
+			}
+			if local.Cardinality() != 1000 {
+				t.Errorf("Expected 1000 elements, got %d", local.Cardinality())
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if s.Cardinality() != 1000 || !s.Contains(0, 999) {
+		t.Error("Concurrent versions should leave the shared set unchanged")
+	}
+}
@@ -0,0 +1,228 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MarshalJSONObject returns s encoded as a JSON object with a true member
+// for each element, such as {"a":true,"b":true}, keyed by the textual form
+// of the elements: encoding.TextMarshaler for the elements implementing it,
+// the Go syntax of numbers and booleans, and strings as they are. Members
+// are written in ascending order of their keys.
+func MarshalJSONObject[T comparable](s ReadOnlySet[T]) ([]byte, error) {
+	keys, err := formatSortedElements[T](s)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			if key == keys[i-1] {
+				return nil, fmt.Errorf("mapset: several elements have the object key %q", key)
+			}
+			buf.WriteByte(',')
+		}
+		b, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+		buf.WriteString(":true")
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// JSONDecodeOptions configures UnmarshalJSONWith.
+type JSONDecodeOptions struct {
+	// DisallowDuplicates makes decoding fail when an element is given
+	// twice, in an array or as the key of two members of an object.
+	DisallowDuplicates bool
+
+	// DisallowNull makes decoding fail on a null document, a null array
+	// element or a null object member. Otherwise a null document adds
+	// nothing, a null array element is decoded as by encoding/json and a
+	// null object member is treated as false.
+	DisallowNull bool
+}
+
+// UnmarshalJSONWith decodes data and adds its elements to s. The elements
+// can be given as a JSON array, as for the UnmarshalJSON method, or as a
+// JSON object of booleans, such as {"a":true,"b":false}, holding the keys
+// of its true members as formatted by MarshalJSONObject. Nothing is added
+// to s when decoding fails.
+func UnmarshalJSONWith[T comparable](data []byte, s Set[T], opts JSONDecodeOptions) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	var vals []T
+	switch tok {
+	case nil:
+		if opts.DisallowNull {
+			return errors.New("mapset: null set")
+		}
+	case json.Delim('['):
+		vals, err = decodeJSONArray[T](dec, opts)
+	case json.Delim('{'):
+		vals, err = decodeJSONObject[T](dec, opts)
+	default:
+		return fmt.Errorf("mapset: expected a JSON array or object, got %v", tok)
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("mapset: unexpected data after the set")
+	}
+	s.Append(vals...)
+	return nil
+}
+
+func decodeJSONArray[T comparable](dec *json.Decoder, opts JSONDecodeOptions) ([]T, error) {
+	var vals []T
+	seen := make(map[T]struct{})
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		if opts.DisallowNull && string(raw) == "null" {
+			return nil, errors.New("mapset: null element")
+		}
+		var v T
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, err
+		}
+		if _, ok := seen[v]; ok {
+			if opts.DisallowDuplicates {
+				return nil, fmt.Errorf("mapset: duplicate element %s", raw)
+			}
+			continue
+		}
+		seen[v] = struct{}{}
+		vals = append(vals, v)
+	}
+	_, err := dec.Token()
+	return vals, err
+}
+
+func decodeJSONObject[T comparable](dec *json.Decoder, opts JSONDecodeOptions) ([]T, error) {
+	// As with encoding/json, the last of duplicate members wins.
+	var keys []T
+	members := make(map[T]bool)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := tok.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+
+		var in bool
+		switch string(raw) {
+		case "true":
+			in = true
+		case "false":
+		case "null":
+			if opts.DisallowNull {
+				return nil, fmt.Errorf("mapset: null member %q", key)
+			}
+		default:
+			return nil, fmt.Errorf("mapset: member %q should be a boolean, got %s", key, raw)
+		}
+
+		v, err := parseElement[T](key)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := members[v]; ok {
+			if opts.DisallowDuplicates {
+				return nil, fmt.Errorf("mapset: duplicate element %q", key)
+			}
+		} else {
+			keys = append(keys, v)
+		}
+		members[v] = in
+	}
+
+	var vals []T
+	for _, v := range keys {
+		if members[v] {
+			vals = append(vals, v)
+		}
+	}
+	_, err := dec.Token()
+	return vals, err
+}
+
+// JSONSet wraps a Set so that it can be the type of a struct field that
+// is decoded from JSON: unlike a field of interface type Set[T], a nil
+// JSONSet gets a new thread-safe set when a JSON array is decoded into it.
+//
+//	type Post struct {
+//		Tags mapset.JSONSet[string] `json:"tags"`
+//	}
+//
+// A JSONSet is encoded like its set, and a nil one as null. Decoding adds
+// the elements of the array to the set, as the UnmarshalJSON method of
+// sets does, and decoding null into a nil JSONSet leaves it nil.
+type JSONSet[T comparable] struct {
+	Set[T]
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s JSONSet[T]) MarshalJSON() ([]byte, error) {
+	if s.Set == nil {
+		return []byte("null"), nil
+	}
+	return s.Set.MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *JSONSet[T]) UnmarshalJSON(b []byte) error {
+	if s.Set == nil {
+		if string(bytes.TrimSpace(b)) == "null" {
+			return nil
+		}
+		s.Set = newThreadSafeSet[T]()
+	}
+	return s.Set.UnmarshalJSON(b)
+}
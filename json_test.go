@@ -0,0 +1,176 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+	"math"
+	"net/netip"
+	"testing"
+)
+
+func Test_MarshalJSONObject(t *testing.T) {
+	b, err := MarshalJSONObject[string](NewSet("b", "a", `q"`))
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if want := `{"a":true,"b":true,"q\"":true}`; string(b) != want {
+		t.Errorf("Expected %s, got %s", want, b)
+	}
+
+	b, err = MarshalJSONObject[int](NewThreadUnsafeSet[int]())
+	if err != nil || string(b) != `{}` {
+		t.Errorf("Expected {}, got %s, %v", b, err)
+	}
+
+	addrs := NewSet(netip.MustParseAddr("::1"), netip.MustParseAddr("10.0.0.1"))
+	b, err = MarshalJSONObject[netip.Addr](addrs)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if want := `{"10.0.0.1":true,"::1":true}`; string(b) != want {
+		t.Errorf("Expected %s, got %s", want, b)
+	}
+	back := NewSet[netip.Addr]()
+	if err := UnmarshalJSONWith(b, back, JSONDecodeOptions{}); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	assertEqual(back, addrs, t)
+
+	if _, err := MarshalJSONObject[float64](NewSet(math.NaN(), math.NaN())); err == nil {
+		t.Error("Elements sharing a key should fail")
+	}
+}
+
+func Test_UnmarshalJSONWith(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []int
+	}{
+		{`[1,2,2,3]`, []int{1, 2, 3}},
+		{`{"1":true,"2":false,"3":true}`, []int{1, 3}},
+		{`{"1":true,"1":false,"2":false,"2":true}`, []int{2}},
+		{`{"4":null}`, nil},
+		{`[null]`, []int{0}},
+		{` null `, nil},
+		{`[]`, nil},
+		{`{}`, nil},
+	}
+	for _, test := range tests {
+		s := NewSet[int]()
+		if err := UnmarshalJSONWith([]byte(test.in), s, JSONDecodeOptions{}); err != nil {
+			t.Errorf("%s: error should be nil: %v", test.in, err)
+			continue
+		}
+		if !s.Equal(NewSet(test.want...)) {
+			t.Errorf("%s: expected %v, got %v", test.in, test.want, s)
+		}
+	}
+
+	for _, in := range []string{`"1"`, `[1`, `{"1":1}`, `{"x":true}`, `["1"]`, `[1] [2]`, ``} {
+		s := NewSet(9)
+		if err := UnmarshalJSONWith([]byte(in), s, JSONDecodeOptions{}); err == nil {
+			t.Errorf("%s should fail", in)
+		}
+		if !s.Equal(NewSet(9)) {
+			t.Errorf("%s: a failed decode should leave the set unchanged, got %v", in, s)
+		}
+	}
+}
+
+func Test_UnmarshalJSONWithStrict(t *testing.T) {
+	dups := JSONDecodeOptions{DisallowDuplicates: true}
+	nulls := JSONDecodeOptions{DisallowNull: true}
+	tests := []struct {
+		in   string
+		opts JSONDecodeOptions
+	}{
+		{`["a","b","a"]`, dups},
+		{`{"a":true,"a":true}`, dups},
+		{`{"a":true,"a":false}`, dups},
+		{`null`, nulls},
+		{`["a",null]`, nulls},
+		{`{"a":null}`, nulls},
+	}
+	for _, test := range tests {
+		if err := UnmarshalJSONWith([]byte(test.in), NewSet[string](), test.opts); err == nil {
+			t.Errorf("%s with %+v should fail", test.in, test.opts)
+		}
+	}
+
+	s := NewSet[string]()
+	err := UnmarshalJSONWith([]byte(`["a","b"]`), s, JSONDecodeOptions{DisallowDuplicates: true, DisallowNull: true})
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	assertEqual(s, NewSet("a", "b"), t)
+}
+
+func Test_JSONSetStructField(t *testing.T) {
+	type post struct {
+		Title string          `json:"title"`
+		Tags  JSONSet[string] `json:"tags"`
+		IDs   JSONSet[int]    `json:"ids"`
+	}
+
+	in := post{Title: "hello", Tags: JSONSet[string]{NewSet("go", "json")}}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+
+	var out post
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if out.Title != "hello" {
+		t.Errorf("Expected title hello, got %s", out.Title)
+	}
+	assertEqual(out.Tags.Set, in.Tags.Set, t)
+	if out.IDs.Set != nil {
+		t.Errorf("A null set should decode as a nil set, got %v", out.IDs.Set)
+	}
+	if _, ok := out.Tags.Set.(*threadSafeSet[string]); !ok {
+		t.Error("A decoded set should be thread-safe")
+	}
+
+	// Methods of the set are promoted.
+	out.Tags.Add("sets")
+	if !out.Tags.ContainsOne("sets") || in.Tags.ContainsOne("sets") {
+		t.Error("A decoded set should not share state with the original")
+	}
+}
+
+func Test_JSONSetKeepsImplementation(t *testing.T) {
+	s := JSONSet[int]{NewThreadUnsafeSet(1)}
+	if err := json.Unmarshal([]byte(`[2,3]`), &s); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if _, ok := s.Set.(*threadUnsafeSet[int]); !ok {
+		t.Error("Decoding should keep the existing set")
+	}
+	assertEqual(s.Set, NewThreadUnsafeSet(1, 2, 3), t)
+}
@@ -0,0 +1,212 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"sort"
+	"sync"
+	"unsafe"
+)
+
+// rwLocked is implemented by the package's thread-safe sets and bags. It
+// exposes their lock and the unsynchronized V it guards, a Set[T] or a
+// Bag[T], so that operations combining several of them can lock every
+// operand up front, in a fixed order, and then read the unsynchronized
+// ones directly.
+type rwLocked[V any] interface {
+	rwMutex() *sync.RWMutex
+	unlocked() V
+}
+
+// snapshotter is implemented by sets that can hand out an immutable view
+// of their current contents. Operations reading such a set use that view,
+// which is consistent and needs no locking.
+type snapshotter[T comparable] interface {
+	snapshot() Collection[T]
+}
+
+// shardLocked is implemented by the sharded sets, which are guarded by one
+// lock per shard instead of a single lock. It exposes those locks and a
+// collection reading the set while all of them are held.
+type shardLocked[T comparable] interface {
+	shardMutexes() []*sync.RWMutex
+	lockedView() Collection[T]
+}
+
+// lessAddr reports whether a is stored at a lower address than b. Whenever
+// several thread-safe sets have to be locked at once, their locks are taken
+// in increasing address order so that no two goroutines can ever wait on
+// each other.
+func lessAddr(a, b *sync.RWMutex) bool {
+	return uintptr(unsafe.Pointer(a)) < uintptr(unsafe.Pointer(b))
+}
+
+// lockOther read-locks other when it is one of the package's thread-safe
+// sets and returns the collection that should be read in its place,
+// together with the matching unlock function. Binary operations use it so
+// they can read a thread-safe operand directly while holding its lock,
+// whatever the receiver's implementation. A snapshotter is read through
+// its snapshot instead, without locking.
+func lockOther[T comparable](other Collection[T]) (Collection[T], func()) {
+	if o, ok := other.(snapshotter[T]); ok {
+		return o.snapshot(), func() {}
+	}
+	if o, ok := other.(Set[T]); ok {
+		return rlockOther(o)
+	}
+	return other, func() {}
+}
+
+// rlockOther read-locks other when it is one of the package's thread-safe
+// sets or bags and returns the V that should be read in its place, together
+// with the matching unlock function.
+func rlockOther[V any](other V) (V, func()) {
+	if o, ok := any(other).(rwLocked[V]); ok {
+		mu := o.rwMutex()
+		mu.RLock()
+		return o.unlocked(), mu.RUnlock
+	}
+	return other, func() {}
+}
+
+// rlockPair read-locks t and, when other is also thread-safe, other as
+// well, in address order. It returns the V that should be read in place
+// of other, together with a function releasing every lock taken.
+func rlockPair[V any](t rwLocked[V], other V) (V, func()) {
+	tmu := t.rwMutex()
+	o, ok := any(other).(rwLocked[V])
+	if !ok {
+		tmu.RLock()
+		return other, tmu.RUnlock
+	}
+
+	omu := o.rwMutex()
+	switch {
+	case omu == tmu:
+		tmu.RLock()
+		return o.unlocked(), tmu.RUnlock
+	case lessAddr(tmu, omu):
+		tmu.RLock()
+		omu.RLock()
+	default:
+		omu.RLock()
+		tmu.RLock()
+	}
+	return o.unlocked(), func() {
+		omu.RUnlock()
+		tmu.RUnlock()
+	}
+}
+
+// lockPairForUpdate write-locks t and, when other is also thread-safe,
+// read-locks other as well. The two locks are always taken in address order
+// so that concurrent in-place updates of two sets from each other cannot
+// deadlock. It returns the collection that should be read in place of other,
+// together with a function releasing every lock taken.
+func lockPairForUpdate[T comparable](t rwLocked[Set[T]], other Set[T]) (Collection[T], func()) {
+	tmu := t.rwMutex()
+	o, ok := other.(rwLocked[Set[T]])
+	if !ok {
+		tmu.Lock()
+		return other, tmu.Unlock
+	}
+
+	omu := o.rwMutex()
+	switch {
+	case omu == tmu:
+		tmu.Lock()
+		return o.unlocked(), tmu.Unlock
+	case lessAddr(tmu, omu):
+		tmu.Lock()
+		omu.RLock()
+	default:
+		omu.RLock()
+		tmu.Lock()
+	}
+	return o.unlocked(), func() {
+		omu.RUnlock()
+		tmu.Unlock()
+	}
+}
+
+// rlockAll read-locks every distinct thread-safe set found in sets, in
+// address order, and returns a collection for each set that can be read
+// without any further locking, together with a function releasing every
+// lock taken. The shards of sharded sets are locked last, as when a
+// sharded set is combined with another thread-safe set.
+func rlockAll[T comparable](sets []ReadOnlySet[T]) ([]Collection[T], func()) {
+	views := make([]Collection[T], len(sets))
+	var locked, shards []*sync.RWMutex
+	for i, s := range sets {
+		switch t := s.(type) {
+		case rwLocked[Set[T]]:
+			views[i] = t.unlocked()
+			locked = append(locked, t.rwMutex())
+		case shardLocked[T]:
+			views[i] = t.lockedView()
+			shards = append(shards, t.shardMutexes()...)
+		case snapshotter[T]:
+			views[i] = t.snapshot()
+		default:
+			views[i] = s
+		}
+	}
+
+	distinct := append(sortDistinct(locked), sortDistinct(shards)...)
+	for _, mu := range distinct {
+		mu.RLock()
+	}
+
+	return views, func() {
+		for _, mu := range distinct {
+			mu.RUnlock()
+		}
+	}
+}
+
+// sortDistinct sorts locks in address order and drops the duplicates.
+func sortDistinct(locks []*sync.RWMutex) []*sync.RWMutex {
+	sort.Slice(locks, func(i, j int) bool {
+		return lessAddr(locks[i], locks[j])
+	})
+	distinct := locks[:0]
+	for i, mu := range locks {
+		if i == 0 || mu != locks[i-1] {
+			distinct = append(distinct, mu)
+		}
+	}
+	return distinct
+}
+
+// unshared returns the set elements should be added to while s is being
+// built by this package. A thread-safe set nobody else can see yet is
+// filled through its unsynchronized set, without paying for its lock.
+func unshared[T comparable](s Set[T]) Set[T] {
+	if t, ok := s.(rwLocked[Set[T]]); ok {
+		return t.unlocked()
+	}
+	return s
+}
@@ -0,0 +1,836 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"sync"
+)
+
+// NewOrderedSet creates and returns a new set with the given elements that
+// remembers the order in which elements were first added. Iteration, String,
+// ToSlice, Pop and the JSON, gob, binary and text encodings all follow that
+// order. Adding an element already in the set does not move it.
+// Operations on the resulting set are thread-safe.
+func NewOrderedSet[T comparable](vals ...T) Set[T] {
+	s := newThreadSafeOrderedSet[T](len(vals))
+	s.oss.Append(vals...)
+	return s
+}
+
+// NewThreadUnsafeOrderedSet creates and returns a new set with the given
+// elements that remembers the order in which elements were first added, as
+// NewOrderedSet does.
+// Operations on the resulting set are not thread-safe.
+func NewThreadUnsafeOrderedSet[T comparable](vals ...T) Set[T] {
+	s := newOrderedSet[T](len(vals))
+	s.Append(vals...)
+	return s
+}
+
+// orderedElement is an element of the linked list keeping the insertion
+// order of an orderedSet.
+type orderedElement[T comparable] struct {
+	next, prev *orderedElement[T]
+	value      T
+}
+
+// orderedSet is the thread-unsafe insertion-ordered set. A map indexes the
+// elements of a circular doubly linked list whose sentinel, root, sits
+// between the last and the first element.
+//
+// Sets returned by its methods follow the order of the receiver, with
+// elements coming only from the argument appended in the argument's
+// iteration order.
+type orderedSet[T comparable] struct {
+	index map[T]*orderedElement[T]
+	root  orderedElement[T]
+}
+
+// Assert concrete type:orderedSet adheres to Set interface.
+var _ Set[string] = (*orderedSet[string])(nil)
+
+func newOrderedSet[T comparable](cardinality int) *orderedSet[T] {
+	s := &orderedSet[T]{}
+	s.reset(cardinality)
+	return s
+}
+
+// reset empties s, which may be a zero orderedSet, making room for
+// cardinality elements.
+func (s *orderedSet[T]) reset(cardinality int) {
+	s.index = make(map[T]*orderedElement[T], cardinality)
+	s.root.next = &s.root
+	s.root.prev = &s.root
+}
+
+// private version of Add which doesn't return a value
+func (s *orderedSet[T]) add(v T) {
+	s.Add(v)
+}
+
+// private version of Remove for an element known to be in the set
+func (s *orderedSet[T]) remove(e *orderedElement[T]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	delete(s.index, e.value)
+}
+
+func (s *orderedSet[T]) Add(v T) bool {
+	if _, ok := s.index[v]; ok {
+		return false
+	}
+	e := &orderedElement[T]{value: v, prev: s.root.prev, next: &s.root}
+	s.root.prev.next = e
+	s.root.prev = e
+	s.index[v] = e
+	return true
+}
+
+func (s *orderedSet[T]) Append(v ...T) int {
+	prevLen := len(s.index)
+	for _, val := range v {
+		s.add(val)
+	}
+	return len(s.index) - prevLen
+}
+
+func (s *orderedSet[T]) Cardinality() int {
+	return len(s.index)
+}
+
+func (s *orderedSet[T]) Clear() {
+	for key := range s.index {
+		delete(s.index, key)
+	}
+	s.root.next = &s.root
+	s.root.prev = &s.root
+}
+
+func (s *orderedSet[T]) Clone() Set[T] {
+	clonedSet := newOrderedSet[T](s.Cardinality())
+	for e := s.root.next; e != &s.root; e = e.next {
+		clonedSet.add(e.value)
+	}
+	return clonedSet
+}
+
+func (s *orderedSet[T]) Contains(v ...T) bool {
+	for _, val := range v {
+		if _, ok := s.index[val]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *orderedSet[T]) ContainsOne(v T) bool {
+	_, ok := s.index[v]
+	return ok
+}
+
+func (s *orderedSet[T]) ContainsAny(v ...T) bool {
+	for _, val := range v {
+		if _, ok := s.index[val]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *orderedSet[T]) ContainsAnyElement(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return containsAnyOf[T](s, c)
+}
+
+func (s *orderedSet[T]) Difference(other Set[T]) Set[T] {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	diff := newOrderedSet[T](0)
+	for e := s.root.next; e != &s.root; e = e.next {
+		if !c.ContainsOne(e.value) {
+			diff.add(e.value)
+		}
+	}
+	return diff
+}
+
+// Each calls cb on every element in insertion order. cb may remove the
+// element it is called with.
+func (s *orderedSet[T]) Each(cb func(T) bool) {
+	for e := s.root.next; e != &s.root; {
+		next := e.next
+		if cb(e.value) {
+			break
+		}
+		e = next
+	}
+}
+
+func (s *orderedSet[T]) Equal(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return equalCollections[T](s, c)
+}
+
+func (s *orderedSet[T]) Intersect(other Set[T]) Set[T] {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	intersection := newOrderedSet[T](0)
+	for e := s.root.next; e != &s.root; e = e.next {
+		if c.ContainsOne(e.value) {
+			intersection.add(e.value)
+		}
+	}
+	return intersection
+}
+
+func (s *orderedSet[T]) IsEmpty() bool {
+	return s.Cardinality() == 0
+}
+
+func (s *orderedSet[T]) IsProperSubset(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.Cardinality() < c.Cardinality() && isSubsetOf[T](s, c)
+}
+
+func (s *orderedSet[T]) IsProperSuperset(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.Cardinality() > c.Cardinality() && isSubsetOf[T](c, s)
+}
+
+func (s *orderedSet[T]) IsSubset(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return isSubsetOf[T](s, c)
+}
+
+func (s *orderedSet[T]) IsSuperset(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return isSubsetOf[T](c, s)
+}
+
+func (s *orderedSet[T]) Iter() <-chan T {
+	return iterCollection[T](s)
+}
+
+func (s *orderedSet[T]) Iterator() *Iterator[T] {
+	return iteratorCollection[T](s)
+}
+
+// Pop removes and returns the earliest added element in case set is not
+// empty, or nil-value of T if set is already empty
+func (s *orderedSet[T]) Pop() (v T, ok bool) {
+	if e := s.root.next; e != &s.root {
+		s.remove(e)
+		return e.value, true
+	}
+	return v, false
+}
+
+func (s *orderedSet[T]) PopN(n int) (items []T, count int) {
+	if n <= 0 || len(s.index) == 0 {
+		return make([]T, 0), 0
+	}
+	if sn := s.Cardinality(); n > sn {
+		n = sn
+	}
+
+	items = make([]T, 0, n)
+	for count < n {
+		v, _ := s.Pop()
+		items = append(items, v)
+		count++
+	}
+	return items, count
+}
+
+func (s *orderedSet[T]) Remove(v T) {
+	if e, ok := s.index[v]; ok {
+		s.remove(e)
+	}
+}
+
+func (s *orderedSet[T]) RemoveAll(i ...T) {
+	for _, elem := range i {
+		s.Remove(elem)
+	}
+}
+
+func (s *orderedSet[T]) String() string {
+	return formatCollection[T](s)
+}
+
+func (s *orderedSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	sd := newOrderedSet[T](0)
+	for e := s.root.next; e != &s.root; e = e.next {
+		if !c.ContainsOne(e.value) {
+			sd.add(e.value)
+		}
+	}
+	c.Each(func(elem T) bool {
+		if !s.ContainsOne(elem) {
+			sd.add(elem)
+		}
+		return false
+	})
+	return sd
+}
+
+func (s *orderedSet[T]) ToSlice() []T {
+	keys := make([]T, 0, s.Cardinality())
+	for e := s.root.next; e != &s.root; e = e.next {
+		keys = append(keys, e.value)
+	}
+	return keys
+}
+
+func (s *orderedSet[T]) Union(other Set[T]) Set[T] {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	unionedSet := s.Clone().(*orderedSet[T])
+	unionedSet.unionUpdate(c)
+	return unionedSet
+}
+
+func (s *orderedSet[T]) UnionUpdate(other Set[T]) int {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.unionUpdate(c)
+}
+
+// private version of UnionUpdate for an already locked collection c
+func (s *orderedSet[T]) unionUpdate(c Collection[T]) int {
+	prevLen := len(s.index)
+	c.Each(func(elem T) bool {
+		s.add(elem)
+		return false
+	})
+	return len(s.index) - prevLen
+}
+
+func (s *orderedSet[T]) IntersectUpdate(other Set[T]) int {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.intersectUpdate(c)
+}
+
+// private version of IntersectUpdate for an already locked collection c
+func (s *orderedSet[T]) intersectUpdate(c Collection[T]) int {
+	prevLen := len(s.index)
+	for e := s.root.next; e != &s.root; e = e.next {
+		if !c.ContainsOne(e.value) {
+			s.remove(e)
+		}
+	}
+	return prevLen - len(s.index)
+}
+
+func (s *orderedSet[T]) DifferenceUpdate(other Set[T]) int {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.differenceUpdate(c)
+}
+
+// private version of DifferenceUpdate for an already locked collection c
+func (s *orderedSet[T]) differenceUpdate(c Collection[T]) int {
+	prevLen := len(s.index)
+	switch {
+	case c == Collection[T](s):
+		s.Clear()
+	case len(s.index) < c.Cardinality():
+		// loop over smaller set
+		for e := s.root.next; e != &s.root; e = e.next {
+			if c.ContainsOne(e.value) {
+				s.remove(e)
+			}
+		}
+	default:
+		c.Each(func(elem T) bool {
+			s.Remove(elem)
+			return false
+		})
+	}
+	return prevLen - len(s.index)
+}
+
+func (s *orderedSet[T]) SymmetricDifferenceUpdate(other Set[T]) (added, removed int) {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.symmetricDifferenceUpdate(c)
+}
+
+// private version of SymmetricDifferenceUpdate for an already locked collection c
+func (s *orderedSet[T]) symmetricDifferenceUpdate(c Collection[T]) (added, removed int) {
+	if c == Collection[T](s) {
+		removed = len(s.index)
+		s.Clear()
+		return added, removed
+	}
+	c.Each(func(elem T) bool {
+		if e, ok := s.index[elem]; ok {
+			s.remove(e)
+			removed++
+		} else {
+			s.add(elem)
+			added++
+		}
+		return false
+	})
+	return added, removed
+}
+
+// MarshalJSON creates a JSON array from the set in insertion order, it
+// marshals all elements
+func (s *orderedSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalCollectionJSON[T](s)
+}
+
+// UnmarshalJSON appends the elements of a JSON array to the set in the
+// order in which they appear.
+func (s *orderedSet[T]) UnmarshalJSON(b []byte) error {
+	var i []T
+	err := json.Unmarshal(b, &i)
+	if err != nil {
+		return err
+	}
+	s.Append(i...)
+
+	return nil
+}
+
+// GobEncode encodes the elements of the set as a gob slice in insertion
+// order.
+func (s *orderedSet[T]) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s.ToSlice()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode replaces the contents of the set with the elements encoded
+// by GobEncode, in the same order.
+func (s *orderedSet[T]) GobDecode(b []byte) error {
+	var i []T
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&i); err != nil {
+		return err
+	}
+	s.reset(len(i))
+	s.Append(i...)
+
+	return nil
+}
+
+// MarshalBinary encodes the set in the compact binary form of
+// threadUnsafeSet, keeping the insertion order. Only sets of integers and
+// strings can be encoded.
+func (s *orderedSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalCollectionBinary[T](s, true)
+}
+
+// UnmarshalBinary replaces the contents of the set with the elements
+// encoded by MarshalBinary, in the order in which they were written.
+func (s *orderedSet[T]) UnmarshalBinary(b []byte) error {
+	i, err := unmarshalBinaryElements[T](b)
+	if err != nil {
+		return err
+	}
+	s.reset(len(i))
+	s.Append(i...)
+
+	return nil
+}
+
+// MarshalText encodes the set in the format returned by DefaultTextFormat,
+// in insertion order.
+func (s *orderedSet[T]) MarshalText() ([]byte, error) {
+	text, err := formatOrderedText[T](s, textFormat)
+	return []byte(text), err
+}
+
+// UnmarshalText replaces the contents of the set with the elements of
+// text in the format returned by DefaultTextFormat, in the order in which
+// they appear.
+func (s *orderedSet[T]) UnmarshalText(text []byte) error {
+	i, err := parseText[T](string(text), textFormat)
+	if err != nil {
+		return err
+	}
+	s.reset(len(i))
+	s.Append(i...)
+
+	return nil
+}
+
+// threadSafeOrderedSet guards an orderedSet with a read-write lock, the way
+// threadSafeSet guards a threadUnsafeSet.
+type threadSafeOrderedSet[T comparable] struct {
+	sync.RWMutex
+	oss *orderedSet[T]
+}
+
+// Assert concrete type:threadSafeOrderedSet takes part in multi-set locking.
+var _ rwLocked[Set[string]] = (*threadSafeOrderedSet[string])(nil)
+
+func newThreadSafeOrderedSet[T comparable](cardinality int) *threadSafeOrderedSet[T] {
+	return &threadSafeOrderedSet[T]{
+		oss: newOrderedSet[T](cardinality),
+	}
+}
+
+func (t *threadSafeOrderedSet[T]) rwMutex() *sync.RWMutex {
+	return &t.RWMutex
+}
+
+func (t *threadSafeOrderedSet[T]) unlocked() Set[T] {
+	return t.oss
+}
+
+func (t *threadSafeOrderedSet[T]) Add(v T) bool {
+	t.Lock()
+	ret := t.oss.Add(v)
+	t.Unlock()
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) Append(v ...T) int {
+	t.Lock()
+	ret := t.oss.Append(v...)
+	t.Unlock()
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) Cardinality() int {
+	t.RLock()
+	defer t.RUnlock()
+	return t.oss.Cardinality()
+}
+
+func (t *threadSafeOrderedSet[T]) Clear() {
+	t.Lock()
+	t.oss.Clear()
+	t.Unlock()
+}
+
+func (t *threadSafeOrderedSet[T]) Clone() Set[T] {
+	t.RLock()
+	unsafeClone := t.oss.Clone().(*orderedSet[T])
+	ret := &threadSafeOrderedSet[T]{oss: unsafeClone}
+	t.RUnlock()
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) Contains(v ...T) bool {
+	t.RLock()
+	ret := t.oss.Contains(v...)
+	t.RUnlock()
+
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) ContainsOne(v T) bool {
+	t.RLock()
+	ret := t.oss.ContainsOne(v)
+	t.RUnlock()
+
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) ContainsAny(v ...T) bool {
+	t.RLock()
+	ret := t.oss.ContainsAny(v...)
+	t.RUnlock()
+
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) ContainsAnyElement(other Set[T]) bool {
+	o, unlock := rlockPair[Set[T]](t, other)
+	ret := t.oss.ContainsAnyElement(o)
+	unlock()
+
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) Difference(other Set[T]) Set[T] {
+	o, unlock := rlockPair[Set[T]](t, other)
+	unsafeDifference := t.oss.Difference(o).(*orderedSet[T])
+	ret := &threadSafeOrderedSet[T]{oss: unsafeDifference}
+	unlock()
+
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) Each(cb func(T) bool) {
+	t.RLock()
+	defer t.RUnlock()
+	t.oss.Each(cb)
+}
+
+func (t *threadSafeOrderedSet[T]) Equal(other Set[T]) bool {
+	o, unlock := rlockPair[Set[T]](t, other)
+	ret := t.oss.Equal(o)
+	unlock()
+
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) Intersect(other Set[T]) Set[T] {
+	o, unlock := rlockPair[Set[T]](t, other)
+	unsafeIntersection := t.oss.Intersect(o).(*orderedSet[T])
+	ret := &threadSafeOrderedSet[T]{oss: unsafeIntersection}
+	unlock()
+
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) IsEmpty() bool {
+	return t.Cardinality() == 0
+}
+
+func (t *threadSafeOrderedSet[T]) IsProperSubset(other Set[T]) bool {
+	o, unlock := rlockPair[Set[T]](t, other)
+	ret := t.oss.IsProperSubset(o)
+	unlock()
+
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) IsProperSuperset(other Set[T]) bool {
+	o, unlock := rlockPair[Set[T]](t, other)
+	ret := t.oss.IsProperSuperset(o)
+	unlock()
+
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) IsSubset(other Set[T]) bool {
+	o, unlock := rlockPair[Set[T]](t, other)
+	ret := t.oss.IsSubset(o)
+	unlock()
+
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) IsSuperset(other Set[T]) bool {
+	o, unlock := rlockPair[Set[T]](t, other)
+	ret := t.oss.IsSuperset(o)
+	unlock()
+
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) Iter() <-chan T {
+	return iterCollection[T](t)
+}
+
+func (t *threadSafeOrderedSet[T]) Iterator() *Iterator[T] {
+	return iteratorCollection[T](t)
+}
+
+func (t *threadSafeOrderedSet[T]) Pop() (T, bool) {
+	t.Lock()
+	defer t.Unlock()
+	return t.oss.Pop()
+}
+
+func (t *threadSafeOrderedSet[T]) PopN(n int) ([]T, int) {
+	t.Lock()
+	defer t.Unlock()
+	return t.oss.PopN(n)
+}
+
+func (t *threadSafeOrderedSet[T]) Remove(v T) {
+	t.Lock()
+	t.oss.Remove(v)
+	t.Unlock()
+}
+
+func (t *threadSafeOrderedSet[T]) RemoveAll(i ...T) {
+	t.Lock()
+	t.oss.RemoveAll(i...)
+	t.Unlock()
+}
+
+func (t *threadSafeOrderedSet[T]) String() string {
+	t.RLock()
+	ret := t.oss.String()
+	t.RUnlock()
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	o, unlock := rlockPair[Set[T]](t, other)
+	unsafeDifference := t.oss.SymmetricDifference(o).(*orderedSet[T])
+	ret := &threadSafeOrderedSet[T]{oss: unsafeDifference}
+	unlock()
+
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) ToSlice() []T {
+	t.RLock()
+	ret := t.oss.ToSlice()
+	t.RUnlock()
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) Union(other Set[T]) Set[T] {
+	o, unlock := rlockPair[Set[T]](t, other)
+	unsafeUnion := t.oss.Union(o).(*orderedSet[T])
+	ret := &threadSafeOrderedSet[T]{oss: unsafeUnion}
+	unlock()
+
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) UnionUpdate(other Set[T]) int {
+	c, unlock := lockPairForUpdate[T](t, other)
+	ret := t.oss.unionUpdate(c)
+	unlock()
+
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) IntersectUpdate(other Set[T]) int {
+	c, unlock := lockPairForUpdate[T](t, other)
+	ret := t.oss.intersectUpdate(c)
+	unlock()
+
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) DifferenceUpdate(other Set[T]) int {
+	c, unlock := lockPairForUpdate[T](t, other)
+	ret := t.oss.differenceUpdate(c)
+	unlock()
+
+	return ret
+}
+
+func (t *threadSafeOrderedSet[T]) SymmetricDifferenceUpdate(other Set[T]) (added, removed int) {
+	c, unlock := lockPairForUpdate[T](t, other)
+	added, removed = t.oss.symmetricDifferenceUpdate(c)
+	unlock()
+
+	return added, removed
+}
+
+func (t *threadSafeOrderedSet[T]) MarshalJSON() ([]byte, error) {
+	t.RLock()
+	b, err := t.oss.MarshalJSON()
+	t.RUnlock()
+
+	return b, err
+}
+
+func (t *threadSafeOrderedSet[T]) UnmarshalJSON(p []byte) error {
+	t.Lock()
+	err := t.oss.UnmarshalJSON(p)
+	t.Unlock()
+
+	return err
+}
+
+func (t *threadSafeOrderedSet[T]) GobEncode() ([]byte, error) {
+	t.RLock()
+	b, err := t.oss.GobEncode()
+	t.RUnlock()
+
+	return b, err
+}
+
+func (t *threadSafeOrderedSet[T]) GobDecode(p []byte) error {
+	t.Lock()
+	if t.oss == nil {
+		t.oss = newOrderedSet[T](0)
+	}
+	err := t.oss.GobDecode(p)
+	t.Unlock()
+
+	return err
+}
+
+func (t *threadSafeOrderedSet[T]) MarshalBinary() ([]byte, error) {
+	t.RLock()
+	b, err := t.oss.MarshalBinary()
+	t.RUnlock()
+
+	return b, err
+}
+
+func (t *threadSafeOrderedSet[T]) UnmarshalBinary(p []byte) error {
+	t.Lock()
+	if t.oss == nil {
+		t.oss = newOrderedSet[T](0)
+	}
+	err := t.oss.UnmarshalBinary(p)
+	t.Unlock()
+
+	return err
+}
+
+func (t *threadSafeOrderedSet[T]) MarshalText() ([]byte, error) {
+	t.RLock()
+	b, err := t.oss.MarshalText()
+	t.RUnlock()
+
+	return b, err
+}
+
+func (t *threadSafeOrderedSet[T]) UnmarshalText(p []byte) error {
+	t.Lock()
+	if t.oss == nil {
+		t.oss = newOrderedSet[T](0)
+	}
+	err := t.oss.UnmarshalText(p)
+	t.Unlock()
+
+	return err
+}
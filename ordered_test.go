@@ -0,0 +1,199 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// Assert interface: ordered sets can be updated in place.
+var (
+	_ Updater[int] = (*orderedSet[int])(nil)
+	_ Updater[int] = (*threadSafeOrderedSet[int])(nil)
+)
+
+func Test_OrderedSetInsertionOrder(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...string) Set[string]) {
+		s := ctor("c", "a", "b")
+		s.Add("a")
+		s.Append("e", "d", "c")
+
+		if got := s.ToSlice(); !reflect.DeepEqual(got, []string{"c", "a", "b", "e", "d"}) {
+			t.Errorf("ToSlice should follow insertion order, got %v", got)
+		}
+		if got := s.String(); got != "Set{c, a, b, e, d}" {
+			t.Errorf("String should follow insertion order, got %s", got)
+		}
+		b, err := s.MarshalJSON()
+		if err != nil {
+			t.Errorf("Error should be nil: %v", err)
+		}
+		if string(b) != `["c","a","b","e","d"]` {
+			t.Errorf("MarshalJSON should follow insertion order, got %s", b)
+		}
+
+		s.Remove("c")
+		s.Add("c")
+		var each []string
+		s.Each(func(v string) bool {
+			each = append(each, v)
+			return false
+		})
+		if !reflect.DeepEqual(each, []string{"a", "b", "e", "d", "c"}) {
+			t.Errorf("A removed and re-added element should move to the end, got %v", each)
+		}
+
+		var iter []string
+		for v := range s.Iter() {
+			iter = append(iter, v)
+		}
+		if !reflect.DeepEqual(iter, each) {
+			t.Errorf("Iter should follow insertion order, got %v", iter)
+		}
+
+		if v, ok := s.Pop(); !ok || v != "a" {
+			t.Errorf("Pop should remove the earliest added element, got %v", v)
+		}
+		if items, n := s.PopN(2); n != 2 || !reflect.DeepEqual(items, []string{"b", "e"}) {
+			t.Errorf("PopN should remove the earliest added elements, got %v", items)
+		}
+		if got := s.ToSlice(); !reflect.DeepEqual(got, []string{"d", "c"}) {
+			t.Errorf("Expected [d c] to remain, got %v", got)
+		}
+
+		clone := s.Clone()
+		if !reflect.DeepEqual(clone.ToSlice(), s.ToSlice()) {
+			t.Error("Clone should keep the order of the original set")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewOrderedSet[string])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeOrderedSet[string])
+	})
+}
+
+func Test_OrderedSetOperations(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		a := ctor(5, 1, 4, 2)
+		b := NewThreadUnsafeOrderedSet(9, 4, 8, 5)
+
+		expect := func(name string, s Set[int], want []int) {
+			if got := s.ToSlice(); !reflect.DeepEqual(got, want) {
+				t.Errorf("%s should be %v, got %v", name, want, got)
+			}
+			if reflect.TypeOf(s) != reflect.TypeOf(a) {
+				t.Errorf("%s should use the same implementation as the receiver", name)
+			}
+		}
+		expect("Union", a.Union(b), []int{5, 1, 4, 2, 9, 8})
+		expect("Intersect", a.Intersect(b), []int{5, 4})
+		expect("Difference", a.Difference(b), []int{1, 2})
+		expect("SymmetricDifference", a.SymmetricDifference(b), []int{1, 2, 9, 8})
+		expect("Filter", Filter[int](a, func(v int) bool { return v > 1 }), []int{5, 4, 2})
+
+		if !a.Equal(NewSet(1, 2, 4, 5)) || !NewSet(1, 2, 4, 5).Equal(a) {
+			t.Error("Equal should not depend on the order of the elements")
+		}
+		if !a.IsSuperset(NewThreadUnsafeSet(1, 2)) || !a.ContainsAnyElement(NewSet(2)) {
+			t.Error("Ordered sets should combine with the other implementations")
+		}
+
+		if added, removed := SymmetricDifferenceUpdate(a, b); added != 2 || removed != 2 {
+			t.Errorf("SymmetricDifferenceUpdate should have added 2 and removed 2, got %d and %d", added, removed)
+		}
+		expect("SymmetricDifferenceUpdate", a, []int{1, 2, 9, 8})
+		if n := UnionUpdate(a, NewThreadUnsafeOrderedSet(3, 1)); n != 1 {
+			t.Errorf("UnionUpdate should have added 1 element, added %d", n)
+		}
+		expect("UnionUpdate", a, []int{1, 2, 9, 8, 3})
+		if n := IntersectUpdate(a, NewSet(3, 2, 8)); n != 2 {
+			t.Errorf("IntersectUpdate should have removed 2 elements, removed %d", n)
+		}
+		expect("IntersectUpdate", a, []int{2, 8, 3})
+		if n := DifferenceUpdate(a, a); n != 3 || !a.IsEmpty() {
+			t.Errorf("DifferenceUpdate with itself should empty the set, removed %d", n)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewOrderedSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeOrderedSet[int])
+	})
+}
+
+func Test_OrderedSetUnmarshalJSON(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		s := ctor(7)
+		if err := json.Unmarshal([]byte(`[3, 1, 2, 1]`), s); err != nil {
+			t.Errorf("Error should be nil: %v", err)
+		}
+		if got := s.ToSlice(); !reflect.DeepEqual(got, []int{7, 3, 1, 2}) {
+			t.Errorf("UnmarshalJSON should append in document order, got %v", got)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewOrderedSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeOrderedSet[int])
+	})
+}
+
+func Test_OrderedSetConcurrent(t *testing.T) {
+	s, ss := NewOrderedSet[int](), NewSet[int]()
+	ints := rand.Perm(N)
+
+	var wg sync.WaitGroup
+	for _, v := range ints {
+		wg.Add(2)
+		go func(v int) {
+			s.Add(v)
+			UnionUpdate(ss, s)
+			wg.Done()
+		}(v)
+		go func() {
+			s.Union(ss)
+			s.Equal(ss)
+			s.ToSlice()
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	if s.Cardinality() != N || !s.Equal(ss) {
+		t.Errorf("Expected %d elements in both sets, got %d and %d", N, s.Cardinality(), ss.Cardinality())
+	}
+}
@@ -0,0 +1,522 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"reflect"
+	"sort"
+	"unsafe"
+)
+
+// PerfectKey is the set of element types a PerfectSet can hold.
+type PerfectKey interface {
+	~string | Integer
+}
+
+// PerfectSet is a read-only set built around a minimal perfect hash
+// function: every element has its own slot in a table exactly as large as
+// the set, so a membership test hashes the value once, reads one
+// displacement and compares a single element, without any locking. It
+// suits large sets that are built once, such as allowlists, and then read
+// millions of times from many goroutines.
+//
+// A PerfectSet is built by NewPerfectSet and can be serialized with
+// MarshalBinary, so it can be built offline and loaded at startup with
+// UnmarshalBinary. Set algebra on it returns new thread-safe sets.
+//
+// The zero value is an empty set.
+type PerfectSet[T PerfectKey] struct {
+	seed uint64
+	// disp holds, for each bucket, either the displacement to hash its
+	// elements with or, when negative, the slot of its only element
+	// minus one.
+	disp []int32
+	keys []T
+	// isString and intSize describe how T is hashed and encoded.
+	isString bool
+	intSize  int
+}
+
+// Assert concrete type:PerfectSet adheres to ReadOnlySet interface.
+var _ ReadOnlySet[string] = (*PerfectSet[string])(nil)
+
+const (
+	// perfectBucketSize is the average number of elements per bucket.
+	perfectBucketSize = 4
+	// perfectMaxDisplacement bounds the search for a bucket's
+	// displacement before the build starts over with another seed.
+	perfectMaxDisplacement = 1 << 20
+	perfectMaxAttempts     = 16
+)
+
+// NewPerfectSet builds a PerfectSet holding the elements of s. A
+// thread-safe s is read under a single read lock. Building takes time
+// linear in the size of s, and the result only depends on the elements, so
+// the same input always produces the same binary form.
+func NewPerfectSet[T PerfectKey](s Set[T]) (*PerfectSet[T], error) {
+	c, unlock := lockOther[T](s)
+	keys := make([]T, 0, c.Cardinality())
+	c.Each(func(elem T) bool {
+		keys = append(keys, elem)
+		return false
+	})
+	unlock()
+
+	p := newPerfectSet[T]()
+	for attempt := uint64(1); attempt <= perfectMaxAttempts; attempt++ {
+		p.seed = mix64(attempt)
+		if p.build(keys) {
+			return p, nil
+		}
+	}
+	return nil, errors.New("mapset: could not build a perfect hash for the set")
+}
+
+func newPerfectSet[T PerfectKey]() *PerfectSet[T] {
+	var zero T
+	p := &PerfectSet[T]{isString: reflect.TypeOf(zero).Kind() == reflect.String}
+	if !p.isString {
+		p.intSize = int(unsafe.Sizeof(zero))
+	}
+	return p
+}
+
+// mix64 is the finalizer of the SplitMix64 generator, which spreads every
+// input bit over the whole output.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// reduce maps x uniformly onto [0, n) without a division.
+func reduce(x uint64, n int) int {
+	hi, _ := bits.Mul64(x, uint64(n))
+	return int(hi)
+}
+
+// intBits returns the bits of an integer v of intSize bytes.
+func intBits[T PerfectKey](v T, intSize int) uint64 {
+	return loadBits(unsafe.Pointer(&v), intSize)
+}
+
+// hash returns the hash of v for the set's seed. It is the same on every
+// platform, as the binary form relies on it.
+func (p *PerfectSet[T]) hash(v T) uint64 {
+	if !p.isString {
+		return mix64(intBits(v, p.intSize) ^ p.seed)
+	}
+	s := *(*string)(unsafe.Pointer(&v))
+	h := p.seed ^ uint64(len(s))
+	for ; len(s) >= 8; s = s[8:] {
+		chunk := uint64(s[0]) | uint64(s[1])<<8 | uint64(s[2])<<16 | uint64(s[3])<<24 |
+			uint64(s[4])<<32 | uint64(s[5])<<40 | uint64(s[6])<<48 | uint64(s[7])<<56
+		h = mix64(h ^ chunk)
+	}
+	var tail uint64
+	for i := len(s) - 1; i >= 0; i-- {
+		tail = tail<<8 | uint64(s[i])
+	}
+	return mix64(h ^ tail)
+}
+
+// slot returns the only slot v can occupy, given its hash h.
+func (p *PerfectSet[T]) slot(h uint64) int {
+	d := p.disp[reduce(h, len(p.disp))]
+	if d < 0 {
+		return int(-d - 1)
+	}
+	return reduce(mix64(h^uint64(d)*0x9e3779b97f4a7c15), len(p.keys))
+}
+
+// build lays out keys with the current seed following the
+// hash-and-displace scheme: keys are spread over buckets, and the buckets,
+// largest first, each look for a displacement sending all of their keys
+// to free slots. Buckets holding a single key take the next free slot
+// directly. It reports false if some bucket found no displacement.
+func (p *PerfectSet[T]) build(keys []T) bool {
+	n := len(keys)
+	p.keys = make([]T, n)
+	p.disp = make([]int32, (n+perfectBucketSize-1)/perfectBucketSize+1)
+
+	hashes := make([]uint64, n)
+	buckets := make([][]int, len(p.disp))
+	for i, k := range keys {
+		hashes[i] = p.hash(k)
+		b := reduce(hashes[i], len(p.disp))
+		buckets[b] = append(buckets[b], i)
+	}
+	order := make([]int, len(buckets))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(buckets[order[i]]) > len(buckets[order[j]])
+	})
+
+	occupied := make([]bool, n)
+	slots := make([]int, 0, perfectBucketSize)
+	free := 0
+	for _, b := range order {
+		bucket := buckets[b]
+		switch len(bucket) {
+		case 0:
+			continue
+		case 1:
+			for occupied[free] {
+				free++
+			}
+			occupied[free] = true
+			p.keys[free] = keys[bucket[0]]
+			p.disp[b] = int32(-free - 1)
+			continue
+		}
+
+		placed := false
+		for d := int32(1); d < perfectMaxDisplacement && !placed; d++ {
+			p.disp[b] = d
+			slots = slots[:0]
+			placed = true
+			for _, i := range bucket {
+				s := p.slot(hashes[i])
+				if occupied[s] || containsInt(slots, s) {
+					placed = false
+					break
+				}
+				slots = append(slots, s)
+			}
+		}
+		if !placed {
+			return false
+		}
+		for j, s := range slots {
+			occupied[s] = true
+			p.keys[s] = keys[bucket[j]]
+		}
+	}
+	return true
+}
+
+func containsInt(s []int, v int) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *PerfectSet[T]) Cardinality() int {
+	return len(p.keys)
+}
+
+func (p *PerfectSet[T]) Clone() Set[T] {
+	return p.toSet()
+}
+
+func (p *PerfectSet[T]) toSet() *threadSafeSet[T] {
+	s := newThreadSafeSetWithSize[T](len(p.keys))
+	for _, k := range p.keys {
+		s.uss.Add(k)
+	}
+	return s
+}
+
+func (p *PerfectSet[T]) Contains(v ...T) bool {
+	for _, val := range v {
+		if !p.ContainsOne(val) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *PerfectSet[T]) ContainsOne(v T) bool {
+	if len(p.keys) == 0 {
+		return false
+	}
+	return p.keys[p.slot(p.hash(v))] == v
+}
+
+func (p *PerfectSet[T]) ContainsAny(v ...T) bool {
+	for _, val := range v {
+		if p.ContainsOne(val) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *PerfectSet[T]) ContainsAnyElement(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return containsAnyOf[T](p, c)
+}
+
+func (p *PerfectSet[T]) Difference(other Set[T]) Set[T] {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	diff := newThreadSafeSet[T]()
+	for _, k := range p.keys {
+		if !c.ContainsOne(k) {
+			diff.uss.Add(k)
+		}
+	}
+	return diff
+}
+
+func (p *PerfectSet[T]) Each(cb func(T) bool) {
+	for _, k := range p.keys {
+		if cb(k) {
+			return
+		}
+	}
+}
+
+func (p *PerfectSet[T]) Equal(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return equalCollections[T](p, c)
+}
+
+func (p *PerfectSet[T]) Intersect(other Set[T]) Set[T] {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	intersection := newThreadSafeSet[T]()
+	for _, k := range p.keys {
+		if c.ContainsOne(k) {
+			intersection.uss.Add(k)
+		}
+	}
+	return intersection
+}
+
+func (p *PerfectSet[T]) IsEmpty() bool {
+	return len(p.keys) == 0
+}
+
+func (p *PerfectSet[T]) IsProperSubset(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return p.Cardinality() < c.Cardinality() && isSubsetOf[T](p, c)
+}
+
+func (p *PerfectSet[T]) IsProperSuperset(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return p.Cardinality() > c.Cardinality() && isSubsetOf[T](c, p)
+}
+
+func (p *PerfectSet[T]) IsSubset(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return isSubsetOf[T](p, c)
+}
+
+func (p *PerfectSet[T]) IsSuperset(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return isSubsetOf[T](c, p)
+}
+
+func (p *PerfectSet[T]) Iter() <-chan T {
+	return iterCollection[T](p)
+}
+
+func (p *PerfectSet[T]) Iterator() *Iterator[T] {
+	return iteratorCollection[T](p)
+}
+
+func (p *PerfectSet[T]) String() string {
+	return formatCollection[T](p)
+}
+
+func (p *PerfectSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	sd := p.toSet()
+	sd.uss.symmetricDifferenceUpdate(c)
+	return sd
+}
+
+func (p *PerfectSet[T]) ToSlice() []T {
+	return append([]T(nil), p.keys...)
+}
+
+func (p *PerfectSet[T]) Union(other Set[T]) Set[T] {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	union := p.toSet()
+	union.uss.unionUpdate(c)
+	return union
+}
+
+// MarshalJSON creates a JSON array from the set, it marshals all elements
+func (p *PerfectSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalCollectionJSON[T](p)
+}
+
+// The binary form of a PerfectSet is, with all integers little-endian:
+//
+//	magic      4 bytes, "MSPH"
+//	version    1 byte, currently 1
+//	key size   1 byte: 0 for strings, or the size of the integers in bytes
+//	seed       uint64
+//	buckets    uint32, the number of displacements
+//	elements   uint32
+//	disp       one int32 per bucket
+//	keys       the elements in slot order: strings as a uvarint length
+//	           followed by their bytes, integers as their key size bytes
+const (
+	perfectMagic   = "MSPH"
+	perfectVersion = 1
+)
+
+var errPerfectFormat = errors.New("mapset: malformed PerfectSet data")
+
+// MarshalBinary encodes the set in the binary form described above. It
+// implements encoding.BinaryMarshaler.
+func (p *PerfectSet[T]) MarshalBinary() ([]byte, error) {
+	if p.disp == nil {
+		p = newPerfectSet[T]()
+		p.build(nil)
+	}
+	b := make([]byte, 0, 22+4*len(p.disp)+8*len(p.keys))
+	b = append(b, perfectMagic...)
+	b = append(b, perfectVersion, byte(p.intSize))
+	b = appendUint64(b, p.seed)
+	b = appendUint32(b, uint32(len(p.disp)))
+	b = appendUint32(b, uint32(len(p.keys)))
+	for _, d := range p.disp {
+		b = appendUint32(b, uint32(d))
+	}
+	var buf [binary.MaxVarintLen64]byte
+	for _, k := range p.keys {
+		if p.isString {
+			s := *(*string)(unsafe.Pointer(&k))
+			b = append(b, buf[:binary.PutUvarint(buf[:], uint64(len(s)))]...)
+			b = append(b, s...)
+			continue
+		}
+		v := intBits(k, p.intSize)
+		for i := 0; i < p.intSize; i++ {
+			b = append(b, byte(v>>(8*i)))
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalBinary replaces the contents of the set with data produced by
+// MarshalBinary for the same element type. It implements
+// encoding.BinaryUnmarshaler.
+func (p *PerfectSet[T]) UnmarshalBinary(data []byte) error {
+	r := newPerfectSet[T]()
+	if len(data) < 22 || string(data[:4]) != perfectMagic {
+		return errPerfectFormat
+	}
+	if data[4] != perfectVersion {
+		return errors.New("mapset: unsupported PerfectSet format version")
+	}
+	if int(data[5]) != r.intSize {
+		return fmt.Errorf("mapset: PerfectSet data holds %d-byte keys, expected %d", data[5], r.intSize)
+	}
+	r.seed = binary.LittleEndian.Uint64(data[6:])
+	nb := int(binary.LittleEndian.Uint32(data[14:]))
+	n := int(binary.LittleEndian.Uint32(data[18:]))
+	data = data[22:]
+	if nb < 1 || len(data) < 4*nb {
+		return errPerfectFormat
+	}
+
+	r.disp = make([]int32, nb)
+	for i := range r.disp {
+		r.disp[i] = int32(binary.LittleEndian.Uint32(data[4*i:]))
+		if r.disp[i] < -int32(n) {
+			return errPerfectFormat
+		}
+	}
+	data = data[4*nb:]
+
+	if n > len(data) {
+		return errPerfectFormat
+	}
+	r.keys = make([]T, n)
+	for i := range r.keys {
+		ptr := unsafe.Pointer(&r.keys[i])
+		if r.isString {
+			l, k := binary.Uvarint(data)
+			if k <= 0 || uint64(len(data)-k) < l {
+				return errPerfectFormat
+			}
+			*(*string)(ptr) = string(data[k : k+int(l)])
+			data = data[k+int(l):]
+			continue
+		}
+		if len(data) < r.intSize {
+			return errPerfectFormat
+		}
+		switch r.intSize {
+		case 1:
+			*(*uint8)(ptr) = data[0]
+		case 2:
+			*(*uint16)(ptr) = binary.LittleEndian.Uint16(data)
+		case 4:
+			*(*uint32)(ptr) = binary.LittleEndian.Uint32(data)
+		default:
+			*(*uint64)(ptr) = binary.LittleEndian.Uint64(data)
+		}
+		data = data[r.intSize:]
+	}
+	if len(data) != 0 {
+		return errPerfectFormat
+	}
+	// Every element must sit in the slot its hash leads to, or lookups
+	// would miss it.
+	for i, k := range r.keys {
+		if r.slot(r.hash(k)) != i {
+			return errPerfectFormat
+		}
+	}
+	*p = *r
+	return nil
+}
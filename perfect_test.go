@@ -0,0 +1,173 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+type perfectID string
+
+func Test_PerfectSetLookups(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 2, 5, 100, 10000} {
+		src := NewThreadUnsafeSet[string]()
+		for src.Cardinality() < n {
+			src.Add(strconv.FormatInt(r.Int63(), 36))
+		}
+
+		p, err := NewPerfectSet(src)
+		if err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		if p.Cardinality() != n || len(p.keys) != n {
+			t.Errorf("Expected a table of exactly %d slots, got %d", n, len(p.keys))
+		}
+		src.Each(func(v string) bool {
+			if !p.ContainsOne(v) {
+				t.Errorf("Element %q should be found", v)
+			}
+			return false
+		})
+		for i := 0; i < 1000; i++ {
+			if v := strconv.Itoa(i); p.ContainsOne(v) != src.ContainsOne(v) {
+				t.Errorf("Unexpected membership of %q", v)
+			}
+		}
+		if !p.Equal(src) || !p.Clone().Equal(src) {
+			t.Error("A perfect set should equal its source")
+		}
+	}
+
+	var zero PerfectSet[int]
+	if zero.ContainsOne(0) || !zero.IsEmpty() {
+		t.Error("The zero PerfectSet should be empty")
+	}
+}
+
+func Test_PerfectSetIntegers(t *testing.T) {
+	p8, err := NewPerfectSet(NewSet[int8](-128, -1, 0, 1, 127))
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !p8.Contains(-128, -1, 0, 1, 127) || p8.ContainsAny(2, -2) {
+		t.Error("Unexpected membership of int8 elements")
+	}
+
+	src := NewSet[uint64]()
+	for i := uint64(0); i < 5000; i++ {
+		src.Add(i * 0x9e3779b97f4a7c15)
+	}
+	p, err := NewPerfectSet(src)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	assertEqual[uint64](p.Clone(), src, t)
+	if p.ContainsOne(1) {
+		t.Error("Missing elements should not be found")
+	}
+}
+
+func Test_PerfectSetAlgebra(t *testing.T) {
+	p, err := NewPerfectSet(NewSet(1, 2, 3))
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	other := NewThreadUnsafeSet(3, 4)
+
+	assertEqual(p.Union(other), NewSet(1, 2, 3, 4), t)
+	assertEqual(p.Intersect(other), NewSet(3), t)
+	assertEqual(p.Difference(other), NewSet(1, 2), t)
+	assertEqual(p.SymmetricDifference(other), NewSet(1, 2, 4), t)
+	if !p.ContainsAnyElement(other) || !p.IsProperSubset(NewSet(1, 2, 3, 4)) || !p.IsSuperset(NewSet(1)) {
+		t.Error("Subset relations are wrong")
+	}
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+	u := NewSet[int]()
+	if err := json.Unmarshal(b, u); err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+	assertEqual(u, NewSet(1, 2, 3), t)
+}
+
+func Test_PerfectSetBinary(t *testing.T) {
+	src := NewSet[perfectID]()
+	for i := 0; i < 3000; i++ {
+		src.Add(perfectID("user-" + strconv.Itoa(i*7)))
+	}
+	p, err := NewPerfectSet(src)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+
+	b, err := p.MarshalBinary()
+	if err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+	again, _ := NewPerfectSet(src.Clone())
+	if b2, _ := again.MarshalBinary(); string(b2) != string(b) {
+		t.Error("Building the same set twice should give the same bytes")
+	}
+
+	var u PerfectSet[perfectID]
+	if err := u.UnmarshalBinary(b); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !u.Equal(src) || !u.ContainsOne("user-14") || u.ContainsOne("user-15") {
+		t.Error("A set should survive a binary round trip")
+	}
+
+	var empty PerfectSet[uint16]
+	eb, err := empty.MarshalBinary()
+	if err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+	if err := empty.UnmarshalBinary(eb); err != nil || !empty.IsEmpty() {
+		t.Errorf("The empty set should survive a binary round trip: %v", err)
+	}
+
+	var ints PerfectSet[int32]
+	corrupt := append([]byte(nil), b...)
+	corrupt[len(corrupt)-1] ^= 0xff
+	for _, data := range [][]byte{nil, b[:10], b[:len(b)-1], corrupt} {
+		if err := u.UnmarshalBinary(data); err == nil {
+			t.Errorf("Expected an error decoding %d corrupt bytes", len(data))
+		}
+	}
+	if err := ints.UnmarshalBinary(b); err == nil {
+		t.Error("Expected an error decoding strings as integers")
+	}
+	if !u.Equal(src) {
+		t.Error("A failed decoding should leave the set unchanged")
+	}
+}
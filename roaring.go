@@ -0,0 +1,811 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"unsafe"
+)
+
+// Integer is the set of integer types a RoaringSet can hold.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | Unsigned
+}
+
+// RoaringSet is a compressed set of integers following the Roaring bitmap
+// design. Elements are split into chunks of 2^16 consecutive values, and
+// each chunk is stored in whichever container is smallest for it: a sorted
+// array for sparse chunks, a bitmap for dense ones, or a list of runs for
+// chunks made of long stretches of consecutive values. Large sets of
+// sparse IDs take a few bytes per element, and set algebra between two
+// RoaringSets works a whole chunk at a time.
+//
+// Every method that iterates over the set does so in ascending order, and
+// Pop removes the smallest element. Sets returned by its methods are
+// RoaringSets too.
+//
+// The zero value is an empty set ready to use. Operations on a RoaringSet
+// are not thread-safe.
+type RoaringSet[T Integer] struct {
+	keys       []uint64
+	containers []roaringContainer
+}
+
+// Assert concrete type:RoaringSet adheres to Set interface.
+var _ Set[uint32] = (*RoaringSet[uint32])(nil)
+
+// NewRoaringSet creates and returns a new roaring set with the given
+// elements. Operations on the resulting set are not thread-safe.
+func NewRoaringSet[T Integer](vals ...T) *RoaringSet[T] {
+	s := &RoaringSet[T]{}
+	s.Append(vals...)
+	return s
+}
+
+func (s *RoaringSet[T]) emptyClone() Set[T] {
+	return &RoaringSet[T]{}
+}
+
+// signBit returns the bit flipped to map T onto uint64 in the same order:
+// the sign bit for signed types, and nothing for unsigned ones.
+func signBit[T Integer]() uint64 {
+	var zero T
+	if zero-1 < zero {
+		return 1 << 63
+	}
+	return 0
+}
+
+func encodeRoaring[T Integer](v T) (key uint64, low uint16) {
+	u := uint64(v) ^ signBit[T]()
+	return u >> 16, uint16(u)
+}
+
+func decodeRoaring[T Integer](key uint64, low uint16) T {
+	return T((key<<16 | uint64(low)) ^ signBit[T]())
+}
+
+// find returns the index of the chunk with key, or where it would be
+// inserted.
+func (s *RoaringSet[T]) find(key uint64) (int, bool) {
+	i := sort.Search(len(s.keys), func(i int) bool { return s.keys[i] >= key })
+	return i, i < len(s.keys) && s.keys[i] == key
+}
+
+// appendChunk adds a chunk after all existing ones.
+func (s *RoaringSet[T]) appendChunk(key uint64, c roaringContainer) {
+	s.keys = append(s.keys, key)
+	s.containers = append(s.containers, c)
+}
+
+// replace makes s hold the chunks of r.
+func (s *RoaringSet[T]) replace(r *RoaringSet[T]) {
+	s.keys, s.containers = r.keys, r.containers
+}
+
+// combine merges the chunks of s and o into a new set. Chunks present in
+// both are combined with op, and chunks present in only one of them are
+// copied when the matching keep flag is set.
+func (s *RoaringSet[T]) combine(o *RoaringSet[T], op func(a, b roaringContainer) roaringContainer, keepS, keepO bool) *RoaringSet[T] {
+	r := &RoaringSet[T]{}
+	i, j := 0, 0
+	for i < len(s.keys) || j < len(o.keys) {
+		switch {
+		case j == len(o.keys) || i < len(s.keys) && s.keys[i] < o.keys[j]:
+			if keepS {
+				r.appendChunk(s.keys[i], s.containers[i].clone())
+			}
+			i++
+		case i == len(s.keys) || o.keys[j] < s.keys[i]:
+			if keepO {
+				r.appendChunk(o.keys[j], o.containers[j].clone())
+			}
+			j++
+		default:
+			if c := op(s.containers[i], o.containers[j]); c.cardinality() > 0 {
+				r.appendChunk(s.keys[i], c)
+			}
+			i++
+			j++
+		}
+	}
+	return r
+}
+
+func (s *RoaringSet[T]) Add(v T) bool {
+	key, low := encodeRoaring(v)
+	i, ok := s.find(key)
+	if !ok {
+		s.keys = append(s.keys, 0)
+		copy(s.keys[i+1:], s.keys[i:])
+		s.keys[i] = key
+		s.containers = append(s.containers, nil)
+		copy(s.containers[i+1:], s.containers[i:])
+		s.containers[i] = arrayContainer{low}
+		return true
+	}
+	if s.containers[i].contains(low) {
+		return false
+	}
+	s.containers[i] = s.containers[i].add(low)
+	return true
+}
+
+func (s *RoaringSet[T]) Append(v ...T) int {
+	added := 0
+	for _, val := range v {
+		if s.Add(val) {
+			added++
+		}
+	}
+	return added
+}
+
+func (s *RoaringSet[T]) Cardinality() int {
+	n := 0
+	for _, c := range s.containers {
+		n += c.cardinality()
+	}
+	return n
+}
+
+func (s *RoaringSet[T]) Clear() {
+	s.keys, s.containers = nil, nil
+}
+
+func (s *RoaringSet[T]) Clone() Set[T] {
+	r := &RoaringSet[T]{
+		keys:       append([]uint64(nil), s.keys...),
+		containers: make([]roaringContainer, len(s.containers)),
+	}
+	for i, c := range s.containers {
+		r.containers[i] = c.clone()
+	}
+	return r
+}
+
+func (s *RoaringSet[T]) Contains(v ...T) bool {
+	for _, val := range v {
+		if !s.ContainsOne(val) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *RoaringSet[T]) ContainsOne(v T) bool {
+	key, low := encodeRoaring(v)
+	i, ok := s.find(key)
+	return ok && s.containers[i].contains(low)
+}
+
+func (s *RoaringSet[T]) ContainsAny(v ...T) bool {
+	for _, val := range v {
+		if s.ContainsOne(val) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *RoaringSet[T]) ContainsAnyElement(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	if o, ok := c.(*RoaringSet[T]); ok {
+		for i, key := range s.keys {
+			if j, ok := o.find(key); ok && containerIntersects(s.containers[i], o.containers[j]) {
+				return true
+			}
+		}
+		return false
+	}
+	return containsAnyOf[T](s, c)
+}
+
+func (s *RoaringSet[T]) Difference(other Set[T]) Set[T] {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	if o, ok := c.(*RoaringSet[T]); ok {
+		return s.combine(o, containerDifference, true, false)
+	}
+	diff := &RoaringSet[T]{}
+	s.Each(func(elem T) bool {
+		if !c.ContainsOne(elem) {
+			diff.Add(elem)
+		}
+		return false
+	})
+	return diff
+}
+
+// Each calls cb on every element in ascending order.
+func (s *RoaringSet[T]) Each(cb func(T) bool) {
+	for i, c := range s.containers {
+		key := s.keys[i]
+		if c.each(func(low uint16) bool { return cb(decodeRoaring[T](key, low)) }) {
+			return
+		}
+	}
+}
+
+func (s *RoaringSet[T]) Equal(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	if o, ok := c.(*RoaringSet[T]); ok {
+		if len(s.keys) != len(o.keys) {
+			return false
+		}
+		for i, key := range s.keys {
+			if key != o.keys[i] ||
+				s.containers[i].cardinality() != o.containers[i].cardinality() ||
+				!containerSubset(s.containers[i], o.containers[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return equalCollections[T](s, c)
+}
+
+func (s *RoaringSet[T]) Intersect(other Set[T]) Set[T] {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	if o, ok := c.(*RoaringSet[T]); ok {
+		return s.combine(o, containerIntersect, false, false)
+	}
+	intersection := &RoaringSet[T]{}
+	s.Each(func(elem T) bool {
+		if c.ContainsOne(elem) {
+			intersection.Add(elem)
+		}
+		return false
+	})
+	return intersection
+}
+
+func (s *RoaringSet[T]) IsEmpty() bool {
+	return len(s.keys) == 0
+}
+
+func (s *RoaringSet[T]) IsProperSubset(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.isSubset(c) && !s.isSuperset(c)
+}
+
+func (s *RoaringSet[T]) IsProperSuperset(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.isSuperset(c) && !s.isSubset(c)
+}
+
+func (s *RoaringSet[T]) IsSubset(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.isSubset(c)
+}
+
+// private version of IsSubset for an already locked collection c
+func (s *RoaringSet[T]) isSubset(c Collection[T]) bool {
+	if o, ok := c.(*RoaringSet[T]); ok {
+		for i, key := range s.keys {
+			j, ok := o.find(key)
+			if !ok || !containerSubset(s.containers[i], o.containers[j]) {
+				return false
+			}
+		}
+		return true
+	}
+	return isSubsetOf[T](s, c)
+}
+
+func (s *RoaringSet[T]) IsSuperset(other Set[T]) bool {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.isSuperset(c)
+}
+
+// private version of IsSuperset for an already locked collection c
+func (s *RoaringSet[T]) isSuperset(c Collection[T]) bool {
+	if o, ok := c.(*RoaringSet[T]); ok {
+		return o.isSubset(s)
+	}
+	return isSubsetOf[T](c, s)
+}
+
+func (s *RoaringSet[T]) Iter() <-chan T {
+	return iterCollection[T](s)
+}
+
+func (s *RoaringSet[T]) Iterator() *Iterator[T] {
+	return iteratorCollection[T](s)
+}
+
+// Pop removes and returns the smallest element in case set is not empty,
+// or nil-value of T if set is already empty
+func (s *RoaringSet[T]) Pop() (v T, ok bool) {
+	if s.IsEmpty() {
+		return v, false
+	}
+	s.Each(func(elem T) bool {
+		v = elem
+		return true
+	})
+	s.Remove(v)
+	return v, true
+}
+
+func (s *RoaringSet[T]) PopN(n int) (items []T, count int) {
+	if n <= 0 || s.IsEmpty() {
+		return make([]T, 0), 0
+	}
+	if sn := s.Cardinality(); n > sn {
+		n = sn
+	}
+
+	items = make([]T, 0, n)
+	for count < n {
+		v, _ := s.Pop()
+		items = append(items, v)
+		count++
+	}
+	return items, count
+}
+
+func (s *RoaringSet[T]) Remove(v T) {
+	key, low := encodeRoaring(v)
+	i, ok := s.find(key)
+	if !ok || !s.containers[i].contains(low) {
+		return
+	}
+	if s.containers[i].cardinality() == 1 {
+		s.keys = append(s.keys[:i], s.keys[i+1:]...)
+		s.containers = append(s.containers[:i], s.containers[i+1:]...)
+		return
+	}
+	s.containers[i] = s.containers[i].remove(low)
+}
+
+func (s *RoaringSet[T]) RemoveAll(i ...T) {
+	for _, elem := range i {
+		s.Remove(elem)
+	}
+}
+
+// RunOptimize converts every chunk to whichever container uses the least
+// memory. Set algebra already does this for the chunks it produces; call
+// it after building a set one element at a time, which only switches
+// between arrays and bitmaps.
+func (s *RoaringSet[T]) RunOptimize() {
+	for i, c := range s.containers {
+		s.containers[i] = optimize(c)
+	}
+}
+
+func (s *RoaringSet[T]) String() string {
+	return formatCollection[T](s)
+}
+
+func (s *RoaringSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	if o, ok := c.(*RoaringSet[T]); ok {
+		return s.combine(o, containerXor, true, true)
+	}
+	sd := s.Clone().(*RoaringSet[T])
+	sd.symmetricDifferenceUpdate(c)
+	return sd
+}
+
+func (s *RoaringSet[T]) ToSlice() []T {
+	return collectionToSlice[T](s)
+}
+
+func (s *RoaringSet[T]) Union(other Set[T]) Set[T] {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	if o, ok := c.(*RoaringSet[T]); ok {
+		return s.combine(o, containerUnion, true, true)
+	}
+	unionedSet := s.Clone().(*RoaringSet[T])
+	unionedSet.unionUpdate(c)
+	return unionedSet
+}
+
+func (s *RoaringSet[T]) UnionUpdate(other Set[T]) int {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.unionUpdate(c)
+}
+
+// private version of UnionUpdate for an already locked collection c
+func (s *RoaringSet[T]) unionUpdate(c Collection[T]) int {
+	prevLen := s.Cardinality()
+	if o, ok := c.(*RoaringSet[T]); ok {
+		s.replace(s.combine(o, containerUnion, true, true))
+	} else {
+		c.Each(func(elem T) bool {
+			s.Add(elem)
+			return false
+		})
+	}
+	return s.Cardinality() - prevLen
+}
+
+func (s *RoaringSet[T]) IntersectUpdate(other Set[T]) int {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	prevLen := s.Cardinality()
+	if o, ok := c.(*RoaringSet[T]); ok {
+		s.replace(s.combine(o, containerIntersect, false, false))
+	} else {
+		intersection := &RoaringSet[T]{}
+		s.Each(func(elem T) bool {
+			if c.ContainsOne(elem) {
+				intersection.Add(elem)
+			}
+			return false
+		})
+		s.replace(intersection)
+	}
+	return prevLen - s.Cardinality()
+}
+
+func (s *RoaringSet[T]) DifferenceUpdate(other Set[T]) int {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	prevLen := s.Cardinality()
+	if o, ok := c.(*RoaringSet[T]); ok {
+		s.replace(s.combine(o, containerDifference, true, false))
+	} else {
+		c.Each(func(elem T) bool {
+			s.Remove(elem)
+			return false
+		})
+	}
+	return prevLen - s.Cardinality()
+}
+
+func (s *RoaringSet[T]) SymmetricDifferenceUpdate(other Set[T]) (added, removed int) {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.symmetricDifferenceUpdate(c)
+}
+
+// private version of SymmetricDifferenceUpdate for an already locked collection c
+func (s *RoaringSet[T]) symmetricDifferenceUpdate(c Collection[T]) (added, removed int) {
+	if o, ok := c.(*RoaringSet[T]); ok {
+		prevLen, otherLen := s.Cardinality(), o.Cardinality()
+		s.replace(s.combine(o, containerXor, true, true))
+		// Every element of o was either added or removed, and the
+		// difference between the two is the change in size.
+		added = (otherLen + s.Cardinality() - prevLen) / 2
+		return added, otherLen - added
+	}
+	c.Each(func(elem T) bool {
+		if s.ContainsOne(elem) {
+			s.Remove(elem)
+			removed++
+		} else {
+			s.Add(elem)
+			added++
+		}
+		return false
+	})
+	return added, removed
+}
+
+// MarshalJSON creates a JSON array from the set in ascending order, it
+// marshals all elements
+func (s *RoaringSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalCollectionJSON[T](s)
+}
+
+// UnmarshalJSON adds the elements of a JSON array to the set.
+func (s *RoaringSet[T]) UnmarshalJSON(b []byte) error {
+	var i []T
+	err := json.Unmarshal(b, &i)
+	if err != nil {
+		return err
+	}
+	s.Append(i...)
+
+	return nil
+}
+
+// The binary form of a RoaringSet is, with all integers little-endian:
+//
+//	magic      4 bytes, "MSRB"
+//	version    1 byte, currently 1
+//	kind       1 byte: 1 for signed integers, 2 for unsigned integers
+//	size       1 byte, the size in bytes of the integers
+//	chunks     uint32, the number of chunks
+//
+// followed by each chunk in ascending key order:
+//
+//	key        uint64, the high 48 bits shared by the chunk's elements
+//	kind       1 byte: 1 for an array, 2 for a bitmap, 3 for runs
+//	array      uint16 count-1, then count uint16 values in ascending order
+//	bitmap     1024 uint64 words, bit i of word w holding value 64*w+i
+//	runs       uint16 count-1, then count pairs of uint16 first and last
+//	           values of each run, in ascending order
+//
+// Elements are keyed by their 64-bit two's complement value, with the sign
+// bit flipped for signed types so that keys sort in numeric order. The
+// format does not depend on the platform, and data may be decoded into a
+// RoaringSet of any integer type able to hold every one of its elements.
+const (
+	roaringMagic   = "MSRB"
+	roaringVersion = 1
+
+	roaringSigned   = 1
+	roaringUnsigned = 2
+
+	roaringArray  = 1
+	roaringBitmap = 2
+	roaringRun    = 3
+)
+
+var errRoaringFormat = errors.New("mapset: malformed RoaringSet data")
+
+// MarshalBinary encodes the set in the portable binary form described
+// above. It implements encoding.BinaryMarshaler.
+func (s *RoaringSet[T]) MarshalBinary() ([]byte, error) {
+	kind, size := roaringKindOf[T]()
+	b := make([]byte, 0, 11+len(s.keys)*16)
+	b = append(b, roaringMagic...)
+	b = append(b, roaringVersion, kind, byte(size))
+	b = appendUint32(b, uint32(len(s.keys)))
+	for i, c := range s.containers {
+		b = appendUint64(b, s.keys[i])
+		switch c := c.(type) {
+		case arrayContainer:
+			b = append(b, roaringArray)
+			b = appendUint16(b, uint16(len(c)-1))
+			for _, x := range c {
+				b = appendUint16(b, x)
+			}
+		case *bitmapContainer:
+			b = append(b, roaringBitmap)
+			for _, w := range c.words {
+				b = appendUint64(b, w)
+			}
+		case runContainer:
+			b = append(b, roaringRun)
+			b = appendUint16(b, uint16(len(c)-1))
+			for _, iv := range c {
+				b = appendUint16(b, iv.start)
+				b = appendUint16(b, iv.last)
+			}
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalBinary replaces the contents of the set with data produced by
+// MarshalBinary. It implements encoding.BinaryUnmarshaler. It returns an
+// error without modifying the set if an element does not fit in T.
+func (s *RoaringSet[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < 5 || string(data[:4]) != roaringMagic {
+		return errRoaringFormat
+	}
+	if data[4] != roaringVersion {
+		return errors.New("mapset: unsupported RoaringSet format version")
+	}
+	if len(data) < 7 {
+		return errRoaringFormat
+	}
+	srcKind := data[5]
+	switch size := data[6]; {
+	case srcKind != roaringSigned && srcKind != roaringUnsigned,
+		size != 1 && size != 2 && size != 4 && size != 8:
+		return errRoaringFormat
+	}
+	data = data[7:]
+	if len(data) < 4 {
+		return errRoaringFormat
+	}
+	n := binary.LittleEndian.Uint32(data)
+	data = data[4:]
+
+	r := &RoaringSet[T]{}
+	for ; n > 0; n-- {
+		if len(data) < 9 {
+			return errRoaringFormat
+		}
+		key := binary.LittleEndian.Uint64(data)
+		if key >= 1<<48 || len(r.keys) > 0 && key <= r.keys[len(r.keys)-1] {
+			return errRoaringFormat
+		}
+		kind := data[8]
+		data = data[9:]
+
+		var c roaringContainer
+		switch kind {
+		case roaringArray, roaringRun:
+			if len(data) < 2 {
+				return errRoaringFormat
+			}
+			count := int(binary.LittleEndian.Uint16(data)) + 1
+			data = data[2:]
+			if kind == roaringArray {
+				a, rest, ok := readArrayContainer(data, count)
+				if !ok {
+					return errRoaringFormat
+				}
+				c, data = a, rest
+			} else {
+				runs, rest, ok := readRunContainer(data, count)
+				if !ok {
+					return errRoaringFormat
+				}
+				c, data = runs, rest
+			}
+		case roaringBitmap:
+			if len(data) < bitmapBytes {
+				return errRoaringFormat
+			}
+			bm := &bitmapContainer{}
+			for i := range bm.words {
+				bm.words[i] = binary.LittleEndian.Uint64(data[8*i:])
+			}
+			bm.recount()
+			if bm.card == 0 {
+				return errRoaringFormat
+			}
+			c, data = bm, data[bitmapBytes:]
+		default:
+			return errRoaringFormat
+		}
+		r.appendChunk(key, c)
+	}
+	if len(data) != 0 {
+		return errRoaringFormat
+	}
+	if err := r.rekey(srcKind == roaringSigned); err != nil {
+		return err
+	}
+	s.replace(r)
+	return nil
+}
+
+// rekey checks that the elements of s, decoded with keys of signed or
+// unsigned elements, all fit in T and moves them to the keys of T. As the
+// elements are sorted and T holds a contiguous range of values, checking
+// the smallest and the largest of them is enough.
+func (s *RoaringSet[T]) rekey(signed bool) error {
+	if len(s.keys) == 0 {
+		return nil
+	}
+	var srcSignBit uint64
+	if signed {
+		srcSignBit = 1 << 63
+	}
+	lo, _ := containerBounds(s.containers[0])
+	_, hi := containerBounds(s.containers[len(s.containers)-1])
+	for _, x := range []uint64{
+		(s.keys[0]<<16 | uint64(lo)) ^ srcSignBit,
+		(s.keys[len(s.keys)-1]<<16 | uint64(hi)) ^ srcSignBit,
+	} {
+		var v T
+		if v = T(x); uint64(v) != x || (signed && int64(x) < 0) != (v < 0) {
+			if signed {
+				return fmt.Errorf("mapset: set data element %d does not fit in %T", int64(x), v)
+			}
+			return fmt.Errorf("mapset: set data element %d does not fit in %T", x, v)
+		}
+	}
+	if flip := (srcSignBit ^ signBit[T]()) >> 16; flip != 0 {
+		for i := range s.keys {
+			s.keys[i] ^= flip
+		}
+	}
+	return nil
+}
+
+// roaringKindOf returns the kind and size under which elements of type T
+// are encoded.
+func roaringKindOf[T Integer]() (kind byte, size int) {
+	var zero T
+	if signBit[T]() != 0 {
+		return roaringSigned, int(unsafe.Sizeof(zero))
+	}
+	return roaringUnsigned, int(unsafe.Sizeof(zero))
+}
+
+// containerBounds returns the smallest and the largest value of c.
+func containerBounds(c roaringContainer) (lo, hi uint16) {
+	first := true
+	c.each(func(x uint16) bool {
+		if first {
+			lo, first = x, false
+		}
+		hi = x
+		return false
+	})
+	return lo, hi
+}
+
+// readArrayContainer decodes count ascending values from data.
+func readArrayContainer(data []byte, count int) (arrayContainer, []byte, bool) {
+	if len(data) < 2*count {
+		return nil, nil, false
+	}
+	a := make(arrayContainer, count)
+	for i := range a {
+		a[i] = binary.LittleEndian.Uint16(data[2*i:])
+		if i > 0 && a[i] <= a[i-1] {
+			return nil, nil, false
+		}
+	}
+	return a, data[2*count:], true
+}
+
+// readRunContainer decodes count ascending, non-adjacent runs from data.
+func readRunContainer(data []byte, count int) (runContainer, []byte, bool) {
+	if len(data) < 4*count {
+		return nil, nil, false
+	}
+	r := make(runContainer, count)
+	for i := range r {
+		r[i].start = binary.LittleEndian.Uint16(data[4*i:])
+		r[i].last = binary.LittleEndian.Uint16(data[4*i+2:])
+		if r[i].last < r[i].start || i > 0 && int(r[i].start) <= int(r[i-1].last)+1 {
+			return nil, nil, false
+		}
+	}
+	return r, data[4*count:], true
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v), byte(v>>8))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v)), uint32(v>>32))
+}
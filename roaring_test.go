@@ -0,0 +1,263 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// Assert interface: a RoaringSet can be updated in place.
+var _ Updater[int] = (*RoaringSet[int])(nil)
+
+// randomRoaringValues returns values mixing sparse, dense and run-shaped
+// chunks, so that every container kind takes part.
+func randomRoaringValues(r *rand.Rand) []int64 {
+	var vals []int64
+	for i := r.Intn(200); i > 0; i-- {
+		vals = append(vals, r.Int63n(1<<40)-1<<39)
+	}
+	for i := r.Intn(6000); i > 0; i-- {
+		vals = append(vals, 1<<16+r.Int63n(1<<16))
+	}
+	start := r.Int63n(1 << 17)
+	for i := r.Int63n(10000); i > 0; i-- {
+		vals = append(vals, start+i)
+	}
+	return vals
+}
+
+func Test_RoaringSetBasics(t *testing.T) {
+	var s RoaringSet[int32]
+	if !s.IsEmpty() || s.Cardinality() != 0 {
+		t.Error("The zero RoaringSet should be empty")
+	}
+	if n := s.Append(70000, -5, 3, 70000, -1<<31); n != 4 {
+		t.Errorf("Append should add 4 new elements, added %d", n)
+	}
+	if s.Add(3) {
+		t.Error("Add should return false for an existing element")
+	}
+	if !s.Contains(-5, 3, 70000, -1<<31) || s.ContainsOne(4) || s.ContainsOne(-70000) {
+		t.Error("Contains reported the wrong membership")
+	}
+	if got := s.ToSlice(); !reflect.DeepEqual(got, []int32{-1 << 31, -5, 3, 70000}) {
+		t.Errorf("ToSlice should be in ascending order, got %v", got)
+	}
+	if v, ok := s.Pop(); !ok || v != -1<<31 {
+		t.Errorf("Pop should remove the smallest element, got %v", v)
+	}
+	s.Remove(3)
+	s.Remove(4)
+	if got := s.String(); got != "Set{-5, 70000}" {
+		t.Errorf("Unexpected String: %s", got)
+	}
+	if items, n := s.PopN(3); n != 2 || !reflect.DeepEqual(items, []int32{-5, 70000}) {
+		t.Errorf("PopN should remove the smallest elements, got %v", items)
+	}
+	if !s.IsEmpty() {
+		t.Error("The set should be empty once every element is popped")
+	}
+}
+
+func Test_RoaringSetContainers(t *testing.T) {
+	s := NewRoaringSet[uint32]()
+	for i := uint32(0); i < 5000; i++ {
+		s.Add(i * 2)
+	}
+	if _, ok := s.containers[0].(*bitmapContainer); !ok {
+		t.Errorf("A chunk past %d elements should be a bitmap, got %T", arrayMaxSize, s.containers[0])
+	}
+	for i := uint32(0); i < 1000; i++ {
+		s.Remove(i * 2)
+	}
+	if _, ok := s.containers[0].(arrayContainer); !ok {
+		t.Errorf("A chunk back to %d elements should be an array, got %T", arrayMaxSize, s.containers[0])
+	}
+
+	runs := NewRoaringSet[uint32]()
+	for i := uint32(100); i < 60000; i++ {
+		runs.Add(i)
+	}
+	runs.RunOptimize()
+	if r, ok := runs.containers[0].(runContainer); !ok || len(r) != 1 {
+		t.Fatalf("A contiguous chunk should be a single run, got %T", runs.containers[0])
+	}
+	runs.Remove(30000)
+	runs.Add(60000)
+	runs.Add(99)
+	if got := runs.containers[0].(runContainer); !reflect.DeepEqual(got, runContainer{{99, 29999}, {30001, 60000}}) {
+		t.Errorf("Unexpected runs after updates: %v", got)
+	}
+	if runs.Cardinality() != 59901 {
+		t.Errorf("Expected 59901 elements, got %d", runs.Cardinality())
+	}
+}
+
+func Test_RoaringSetMatchesMapSet(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 30; i++ {
+		va, vb := randomRoaringValues(r), randomRoaringValues(r)
+		a, b := NewRoaringSet(va...), NewRoaringSet(vb...)
+		if i%2 == 0 {
+			a.RunOptimize()
+		}
+		ma, mb := NewThreadUnsafeSet(va...), NewThreadUnsafeSet(vb...)
+
+		for _, op := range []struct {
+			name     string
+			got, exp Set[int64]
+		}{
+			{"Union", a.Union(b), ma.Union(mb)},
+			{"Intersect", a.Intersect(b), ma.Intersect(mb)},
+			{"Difference", a.Difference(b), ma.Difference(mb)},
+			{"SymmetricDifference", a.SymmetricDifference(b), ma.SymmetricDifference(mb)},
+			{"Union with map set", a.Union(mb), ma.Union(mb)},
+			{"Difference with map set", a.Difference(mb), ma.Difference(mb)},
+		} {
+			if op.got.Cardinality() != op.exp.Cardinality() || !op.exp.Equal(op.got) {
+				t.Fatalf("%s mismatch: got %d elements, expected %d", op.name, op.got.Cardinality(), op.exp.Cardinality())
+			}
+			if _, ok := op.got.(*RoaringSet[int64]); !ok {
+				t.Fatalf("%s should return a RoaringSet, got %T", op.name, op.got)
+			}
+		}
+		if a.IsSubset(b) != ma.IsSubset(mb) || a.ContainsAnyElement(b) != ma.ContainsAnyElement(mb) {
+			t.Fatal("Subset relations should match the map-based set")
+		}
+		if !a.Union(b).IsSuperset(b) || !a.Intersect(b).IsSubset(a) {
+			t.Fatal("Union and Intersect results should contain and be contained by their inputs")
+		}
+
+		c := a.Clone().(*RoaringSet[int64])
+		added, removed := c.SymmetricDifferenceUpdate(b)
+		mAdded, mRemoved := SymmetricDifferenceUpdate(ma.Clone(), mb)
+		if added != mAdded || removed != mRemoved {
+			t.Fatalf("SymmetricDifferenceUpdate should report %d and %d, got %d and %d", mAdded, mRemoved, added, removed)
+		}
+	}
+}
+
+func Test_RoaringSetBinary(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	s := NewRoaringSet(randomRoaringValues(r)...)
+	s.RunOptimize()
+
+	b, err := s.MarshalBinary()
+	if err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+	u := NewRoaringSet[int64](42)
+	if err := u.UnmarshalBinary(b); err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+	if u.Cardinality() != s.Cardinality() || !u.Equal(s) {
+		t.Error("A set should survive a binary round trip")
+	}
+
+	empty, _ := NewRoaringSet[uint16]().MarshalBinary()
+	if string(empty) != "MSRB\x01\x02\x02\x00\x00\x00\x00" {
+		t.Errorf("Unexpected encoding of the empty set: %q", empty)
+	}
+	one, _ := NewRoaringSet[uint16](0x1234).MarshalBinary()
+	if exp := "MSRB\x01\x02\x02\x01\x00\x00\x00" + "\x00\x00\x00\x00\x00\x00\x00\x00" + "\x01\x00\x00\x34\x12"; string(one) != exp {
+		t.Errorf("Unexpected encoding of a single element: %q", one)
+	}
+
+	for _, data := range [][]byte{
+		nil,
+		[]byte("MSRB"),
+		[]byte("XXXX\x01\x00\x00\x00\x00"),
+		[]byte("MSRB\x02\x02\x02\x00\x00\x00\x00"),
+		[]byte("MSRB\x01\x03\x02\x00\x00\x00\x00"),
+		[]byte("MSRB\x01\x02\x03\x00\x00\x00\x00"),
+		[]byte("MSRB\x01\x02\x02\x00\x00\x00"),
+		[]byte("MSRB\x01\x01\x08\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x01\x00\x01\x00\x00\x05\x00"),
+		b[:len(b)-1],
+		append(append([]byte(nil), b...), 0),
+		[]byte("MSRB\x01\x01\x08\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x01\x01\x00\x05\x00\x05\x00"),
+		[]byte("MSRB\x01\x01\x08\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x03\x01\x00\x01\x00\x02\x00\x03\x00\x04\x00"),
+	} {
+		if err := u.UnmarshalBinary(data); err == nil {
+			t.Errorf("Expected an error decoding %q", data)
+		}
+	}
+}
+
+func Test_RoaringSetBinaryElementType(t *testing.T) {
+	b, _ := NewRoaringSet[uint32](1, 65537).MarshalBinary()
+	u := NewRoaringSet[uint16](7)
+	if err := u.UnmarshalBinary(b); err == nil {
+		t.Errorf("Decoding 65537 into a uint16 set should fail, got %v", u.ToSlice())
+	}
+	if !u.Equal(NewRoaringSet[uint16](7)) {
+		t.Errorf("A failed decode should leave the set unchanged, got %v", u.ToSlice())
+	}
+
+	w := &RoaringSet[int64]{}
+	if err := w.UnmarshalBinary(b); err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+	if !w.Equal(NewRoaringSet[int64](1, 65537)) {
+		t.Errorf("Expected [1 65537], got %v", w.ToSlice())
+	}
+
+	b, _ = NewRoaringSet[int](-1, 5).MarshalBinary()
+	if err := (&RoaringSet[uint64]{}).UnmarshalBinary(b); err == nil {
+		t.Error("Decoding -1 into a uint64 set should fail")
+	}
+	b, _ = NewRoaringSet[int](-128, 127).MarshalBinary()
+	i8 := &RoaringSet[int8]{}
+	if err := i8.UnmarshalBinary(b); err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+	if !i8.Equal(NewRoaringSet[int8](-128, 127)) {
+		t.Errorf("Expected [-128 127], got %v", i8.ToSlice())
+	}
+	b, _ = NewRoaringSet[uint64](1<<63, 3).MarshalBinary()
+	if err := (&RoaringSet[int64]{}).UnmarshalBinary(b); err == nil {
+		t.Error("Decoding 1<<63 into an int64 set should fail")
+	}
+}
+
+func Test_RoaringSetJSON(t *testing.T) {
+	s := NewRoaringSet[int](100000, -3, 7)
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+	if string(b) != "[-3,7,100000]" {
+		t.Errorf("Expected [-3,7,100000], got %s", b)
+	}
+
+	u := &RoaringSet[int]{}
+	if err := json.Unmarshal(b, u); err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+	assertEqual[int](u, s, t)
+}
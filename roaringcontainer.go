@@ -0,0 +1,516 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"math/bits"
+	"sort"
+)
+
+const (
+	// arrayMaxSize is the largest cardinality kept in an array container;
+	// past it a bitmap takes less memory.
+	arrayMaxSize = 4096
+	// bitmapWords is the number of 64-bit words covering one 2^16 chunk.
+	bitmapWords = 1 << 16 / 64
+	// bitmapBytes is the memory used by a bitmap container.
+	bitmapBytes = bitmapWords * 8
+)
+
+// roaringContainer holds the low 16 bits of the elements of a RoaringSet
+// that share the same high bits. add and remove return the container to
+// use from then on, which may have switched representation; add is only
+// called for absent values and remove for present ones.
+type roaringContainer interface {
+	cardinality() int
+	contains(x uint16) bool
+	add(x uint16) roaringContainer
+	remove(x uint16) roaringContainer
+	// each calls cb on every value in ascending order and reports whether
+	// cb stopped the iteration.
+	each(cb func(uint16) bool) bool
+	clone() roaringContainer
+	// numRuns returns the number of runs of consecutive values.
+	numRuns() int
+}
+
+// arrayContainer is a sorted list of values, used for sparse chunks.
+type arrayContainer []uint16
+
+func (a arrayContainer) search(x uint16) int {
+	return sort.Search(len(a), func(i int) bool { return a[i] >= x })
+}
+
+func (a arrayContainer) cardinality() int {
+	return len(a)
+}
+
+func (a arrayContainer) contains(x uint16) bool {
+	i := a.search(x)
+	return i < len(a) && a[i] == x
+}
+
+func (a arrayContainer) add(x uint16) roaringContainer {
+	if len(a) == arrayMaxSize {
+		return toBitmap(a).add(x)
+	}
+	i := a.search(x)
+	a = append(a, 0)
+	copy(a[i+1:], a[i:])
+	a[i] = x
+	return a
+}
+
+func (a arrayContainer) remove(x uint16) roaringContainer {
+	i := a.search(x)
+	return append(a[:i], a[i+1:]...)
+}
+
+func (a arrayContainer) each(cb func(uint16) bool) bool {
+	for _, x := range a {
+		if cb(x) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a arrayContainer) clone() roaringContainer {
+	return append(arrayContainer(nil), a...)
+}
+
+func (a arrayContainer) numRuns() int {
+	n := 0
+	for i, x := range a {
+		if i == 0 || x != a[i-1]+1 {
+			n++
+		}
+	}
+	return n
+}
+
+// bitmapContainer has one bit per possible value, used for dense chunks.
+type bitmapContainer struct {
+	words [bitmapWords]uint64
+	card  int
+}
+
+func (b *bitmapContainer) cardinality() int {
+	return b.card
+}
+
+func (b *bitmapContainer) contains(x uint16) bool {
+	return b.words[x>>6]&(1<<(x&63)) != 0
+}
+
+func (b *bitmapContainer) add(x uint16) roaringContainer {
+	b.words[x>>6] |= 1 << (x & 63)
+	b.card++
+	return b
+}
+
+func (b *bitmapContainer) remove(x uint16) roaringContainer {
+	b.words[x>>6] &^= 1 << (x & 63)
+	b.card--
+	if b.card <= arrayMaxSize {
+		return toArray(b)
+	}
+	return b
+}
+
+func (b *bitmapContainer) each(cb func(uint16) bool) bool {
+	for i, w := range b.words {
+		for w != 0 {
+			if cb(uint16(i<<6 + bits.TrailingZeros64(w))) {
+				return true
+			}
+			w &= w - 1
+		}
+	}
+	return false
+}
+
+func (b *bitmapContainer) clone() roaringContainer {
+	c := *b
+	return &c
+}
+
+func (b *bitmapContainer) numRuns() int {
+	n := 0
+	prev := uint64(0)
+	for _, w := range b.words {
+		n += bits.OnesCount64(w &^ (w<<1 | prev>>63))
+		prev = w
+	}
+	return n
+}
+
+// recount recomputes the cached cardinality after a word-level operation.
+func (b *bitmapContainer) recount() {
+	b.card = 0
+	for _, w := range b.words {
+		b.card += bits.OnesCount64(w)
+	}
+}
+
+// eachRangeWord calls f with the index and mask of every word overlapping
+// the inclusive range [start, last].
+func eachRangeWord(start, last uint16, f func(i int, mask uint64)) {
+	first, end := int(start>>6), int(last>>6)
+	for i := first; i <= end; i++ {
+		mask := ^uint64(0)
+		if i == first {
+			mask &= ^uint64(0) << (start & 63)
+		}
+		if i == end {
+			mask &= ^uint64(0) >> (63 - last&63)
+		}
+		f(i, mask)
+	}
+}
+
+// or adds the values of c to b.
+func (b *bitmapContainer) or(c roaringContainer) {
+	switch c := c.(type) {
+	case *bitmapContainer:
+		for i, w := range c.words {
+			b.words[i] |= w
+		}
+	case runContainer:
+		for _, iv := range c {
+			eachRangeWord(iv.start, iv.last, func(i int, mask uint64) { b.words[i] |= mask })
+		}
+	default:
+		c.each(func(x uint16) bool {
+			b.words[x>>6] |= 1 << (x & 63)
+			return false
+		})
+	}
+	b.recount()
+}
+
+// and keeps only the values of b that are also in c.
+func (b *bitmapContainer) and(c roaringContainer) {
+	o := toBitmap(c)
+	for i, w := range o.words {
+		b.words[i] &= w
+	}
+	b.recount()
+}
+
+// andNot removes the values of c from b.
+func (b *bitmapContainer) andNot(c roaringContainer) {
+	switch c := c.(type) {
+	case *bitmapContainer:
+		for i, w := range c.words {
+			b.words[i] &^= w
+		}
+	case runContainer:
+		for _, iv := range c {
+			eachRangeWord(iv.start, iv.last, func(i int, mask uint64) { b.words[i] &^= mask })
+		}
+	default:
+		c.each(func(x uint16) bool {
+			b.words[x>>6] &^= 1 << (x & 63)
+			return false
+		})
+	}
+	b.recount()
+}
+
+// xor toggles the values of c in b.
+func (b *bitmapContainer) xor(c roaringContainer) {
+	switch c := c.(type) {
+	case *bitmapContainer:
+		for i, w := range c.words {
+			b.words[i] ^= w
+		}
+	case runContainer:
+		for _, iv := range c {
+			eachRangeWord(iv.start, iv.last, func(i int, mask uint64) { b.words[i] ^= mask })
+		}
+	default:
+		c.each(func(x uint16) bool {
+			b.words[x>>6] ^= 1 << (x & 63)
+			return false
+		})
+	}
+	b.recount()
+}
+
+// interval16 is an inclusive range of consecutive values.
+type interval16 struct {
+	start, last uint16
+}
+
+// runContainer is a sorted list of disjoint, non-adjacent ranges, used for
+// chunks made of long runs of consecutive values.
+type runContainer []interval16
+
+func (r runContainer) search(x uint16) int {
+	return sort.Search(len(r), func(i int) bool { return r[i].last >= x })
+}
+
+func (r runContainer) cardinality() int {
+	n := 0
+	for _, iv := range r {
+		n += int(iv.last) - int(iv.start) + 1
+	}
+	return n
+}
+
+func (r runContainer) contains(x uint16) bool {
+	i := r.search(x)
+	return i < len(r) && r[i].start <= x
+}
+
+func (r runContainer) add(x uint16) roaringContainer {
+	i := r.search(x)
+	joinsLeft := i > 0 && r[i-1].last+1 == x
+	joinsRight := i < len(r) && r[i].start == x+1
+	switch {
+	case joinsLeft && joinsRight:
+		r[i-1].last = r[i].last
+		return append(r[:i], r[i+1:]...)
+	case joinsLeft:
+		r[i-1].last = x
+		return r
+	case joinsRight:
+		r[i].start = x
+		return r
+	}
+	r = append(r, interval16{})
+	copy(r[i+1:], r[i:])
+	r[i] = interval16{x, x}
+	return optimize(r)
+}
+
+func (r runContainer) remove(x uint16) roaringContainer {
+	i := r.search(x)
+	switch iv := r[i]; {
+	case iv.start == iv.last:
+		return append(r[:i], r[i+1:]...)
+	case x == iv.start:
+		r[i].start++
+	case x == iv.last:
+		r[i].last--
+	default:
+		r = append(r, interval16{})
+		copy(r[i+1:], r[i:])
+		r[i].last = x - 1
+		r[i+1].start = x + 1
+		return optimize(r)
+	}
+	return r
+}
+
+func (r runContainer) each(cb func(uint16) bool) bool {
+	for _, iv := range r {
+		for x := iv.start; ; x++ {
+			if cb(x) {
+				return true
+			}
+			if x == iv.last {
+				break
+			}
+		}
+	}
+	return false
+}
+
+func (r runContainer) clone() roaringContainer {
+	return append(runContainer(nil), r...)
+}
+
+func (r runContainer) numRuns() int {
+	return len(r)
+}
+
+// toArray returns the values of c as a new array container.
+func toArray(c roaringContainer) arrayContainer {
+	a := make(arrayContainer, 0, c.cardinality())
+	c.each(func(x uint16) bool {
+		a = append(a, x)
+		return false
+	})
+	return a
+}
+
+// toBitmap returns the values of c as a new bitmap container.
+func toBitmap(c roaringContainer) *bitmapContainer {
+	if b, ok := c.(*bitmapContainer); ok {
+		return b.clone().(*bitmapContainer)
+	}
+	b := &bitmapContainer{}
+	b.or(c)
+	return b
+}
+
+// toRun returns the values of c as a new run container.
+func toRun(c roaringContainer) runContainer {
+	r := make(runContainer, 0, c.numRuns())
+	c.each(func(x uint16) bool {
+		if n := len(r); n > 0 && r[n-1].last+1 == x {
+			r[n-1].last = x
+		} else {
+			r = append(r, interval16{x, x})
+		}
+		return false
+	})
+	return r
+}
+
+// optimize returns c in whichever representation uses the least memory.
+func optimize(c roaringContainer) roaringContainer {
+	card := c.cardinality()
+	size := bitmapBytes
+	if card <= arrayMaxSize {
+		size = 2 * card
+	}
+	if 4*c.numRuns() < size {
+		if _, ok := c.(runContainer); ok {
+			return c
+		}
+		return toRun(c)
+	}
+	if card <= arrayMaxSize {
+		if _, ok := c.(arrayContainer); ok {
+			return c
+		}
+		return toArray(c)
+	}
+	if _, ok := c.(*bitmapContainer); ok {
+		return c
+	}
+	return toBitmap(c)
+}
+
+// mergeArrays returns the sorted union of two array containers.
+func mergeArrays(a, b arrayContainer) arrayContainer {
+	r := make(arrayContainer, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			r = append(r, a[i])
+			i++
+		case a[i] > b[j]:
+			r = append(r, b[j])
+			j++
+		default:
+			r = append(r, a[i])
+			i++
+			j++
+		}
+	}
+	r = append(r, a[i:]...)
+	return append(r, b[j:]...)
+}
+
+// filterArray returns the values of a whose membership in c equals keep.
+func filterArray(a arrayContainer, c roaringContainer, keep bool) arrayContainer {
+	r := make(arrayContainer, 0, len(a))
+	for _, x := range a {
+		if c.contains(x) == keep {
+			r = append(r, x)
+		}
+	}
+	return r
+}
+
+func containerUnion(a, b roaringContainer) roaringContainer {
+	if x, ok := a.(arrayContainer); ok {
+		if y, ok := b.(arrayContainer); ok && len(x)+len(y) <= arrayMaxSize {
+			return optimize(mergeArrays(x, y))
+		}
+	}
+	r := toBitmap(a)
+	r.or(b)
+	return optimize(r)
+}
+
+func containerIntersect(a, b roaringContainer) roaringContainer {
+	if x, ok := a.(arrayContainer); ok {
+		return optimize(filterArray(x, b, true))
+	}
+	if y, ok := b.(arrayContainer); ok {
+		return optimize(filterArray(y, a, true))
+	}
+	r := toBitmap(a)
+	r.and(b)
+	return optimize(r)
+}
+
+func containerDifference(a, b roaringContainer) roaringContainer {
+	if x, ok := a.(arrayContainer); ok {
+		return optimize(filterArray(x, b, false))
+	}
+	r := toBitmap(a)
+	r.andNot(b)
+	return optimize(r)
+}
+
+func containerXor(a, b roaringContainer) roaringContainer {
+	r := toBitmap(a)
+	r.xor(b)
+	return optimize(r)
+}
+
+// containerSubset reports whether every value of a is also in b.
+func containerSubset(a, b roaringContainer) bool {
+	if a.cardinality() > b.cardinality() {
+		return false
+	}
+	if x, ok := a.(*bitmapContainer); ok {
+		if y, ok := b.(*bitmapContainer); ok {
+			for i, w := range x.words {
+				if w&^y.words[i] != 0 {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return !a.each(func(x uint16) bool { return !b.contains(x) })
+}
+
+// containerIntersects reports whether a and b share any value.
+func containerIntersects(a, b roaringContainer) bool {
+	if x, ok := a.(*bitmapContainer); ok {
+		if y, ok := b.(*bitmapContainer); ok {
+			for i, w := range x.words {
+				if w&y.words[i] != 0 {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	if a.cardinality() > b.cardinality() {
+		a, b = b, a
+	}
+	return a.each(b.contains)
+}
@@ -32,28 +32,56 @@ SOFTWARE.
 // interface. The default implementation is safe for concurrent
 // access, but a non-thread-safe implementation is also provided for
 // programs that can benefit from the slight speed improvement and
-// that can enforce mutual exclusion through other means.
+// that can enforce mutual exclusion through other means. Both also come
+// in an insertion-ordered flavor, see NewOrderedSet.
+//
+// Filter, UnionAll and the other package functions building new sets accept
+// any ReadOnlySet, such as one returned by Freeze. The sets they return use
+// the same implementation as their first argument, or the default
+// thread-safe one when that argument cannot be modified.
+//
+// Filter, Map, Reduce, Any, All and the other functions taking a callback
+// read a thread-safe set from a single consistent snapshot: its read lock
+// is held while the callback runs, so the callback must not modify the set.
 package mapset
 
-// Set is the primary interface provided by the mapset package.  It
-// represents an unordered set of data and a large number of
-// operations that can be applied to that set.
-type Set[T comparable] interface {
-	// Add adds an element to the set. Returns whether
-	// the item was added.
-	Add(val T) bool
+// Collection is the read-only membership and iteration contract the
+// built-in sets rely on when they are combined with a Set implementation
+// that is not one of their own. Any type implementing these three methods
+// (a bitset, a database-backed set, ...) can take part in the binary
+// operations of the built-in sets without being copied first.
+//
+// When the receiver of such an operation is thread-safe, the methods of
+// the argument are called while the receiver's read lock is held, so they
+// must not modify the receiver.
+type Collection[T comparable] interface {
+	// Cardinality returns the number of elements in the collection.
+	Cardinality() int
 
-	// Append multiple elements to the set. Returns
-	// the number of elements added.
-	Append(val ...T) int
+	// ContainsOne returns whether the given item
+	// is in the collection.
+	ContainsOne(val T) bool
+
+	// Each iterates over elements and executes the passed func against each element.
+	// If passed func returns true, stop iteration at the time.
+	Each(func(T) bool)
+}
 
+// ReadOnlySet is the part of the Set interface that cannot modify the set:
+// membership tests, iteration and set algebra returning new sets. Hand out
+// a ReadOnlySet, for instance one returned by Freeze, to code that must
+// not be able to change the set.
+//
+// Methods that take another set as an argument accept any Set[T]
+// implementation, so thread-safe and thread-unsafe sets can be freely
+// mixed. Arguments of a foreign implementation are only accessed through
+// the Collection methods. Methods that return a new set always return one
+// of the same implementation as the receiver, or a thread-safe set when the
+// receiver cannot be modified.
+type ReadOnlySet[T comparable] interface {
 	// Cardinality returns the number of elements in the set.
 	Cardinality() int
 
-	// Clear removes all elements from the set, leaving
-	// the empty set.
-	Clear()
-
 	// Clone returns a clone of the set using the same
 	// implementation, duplicating all keys.
 	Clone() Set[T]
@@ -81,11 +109,6 @@ type Set[T comparable] interface {
 	// and other. The returned set will contain
 	// all elements of this set that are not also
 	// elements of other.
-	//
-	// Note that the argument to Difference
-	// must be of the same type as the receiver
-	// of the method. Otherwise, Difference will
-	// panic.
 	Difference(other Set[T]) Set[T]
 
 	// Equal determines if two sets are equal to each
@@ -93,19 +116,10 @@ type Set[T comparable] interface {
 	// and contain the same elements, they are
 	// considered equal. The order in which
 	// the elements were added is irrelevant.
-	//
-	// Note that the argument to Equal must be
-	// of the same type as the receiver of the
-	// method. Otherwise, Equal will panic.
 	Equal(other Set[T]) bool
 
 	// Intersect returns a new set containing only the elements
 	// that exist only in both sets.
-	//
-	// Note that the argument to Intersect
-	// must be of the same type as the receiver
-	// of the method. Otherwise, Intersect will
-	// panic.
 	Intersect(other Set[T]) Set[T]
 
 	// IsEmpty determines if there are elements in the set.
@@ -113,39 +127,19 @@ type Set[T comparable] interface {
 
 	// IsProperSubset determines if every element in this set is in
 	// the other set but the two sets are not equal.
-	//
-	// Note that the argument to IsProperSubset
-	// must be of the same type as the receiver
-	// of the method. Otherwise, IsProperSubset
-	// will panic.
 	IsProperSubset(other Set[T]) bool
 
 	// IsProperSuperset determines if every element in the other set
 	// is in this set but the two sets are not
 	// equal.
-	//
-	// Note that the argument to IsSuperset
-	// must be of the same type as the receiver
-	// of the method. Otherwise, IsSuperset will
-	// panic.
 	IsProperSuperset(other Set[T]) bool
 
 	// IsSubset determines if every element in this set is in
 	// the other set.
-	//
-	// Note that the argument to IsSubset
-	// must be of the same type as the receiver
-	// of the method. Otherwise, IsSubset will
-	// panic.
 	IsSubset(other Set[T]) bool
 
 	// IsSuperset determines if every element in the other set
 	// is in this set.
-	//
-	// Note that the argument to IsSuperset
-	// must be of the same type as the receiver
-	// of the method. Otherwise, IsSuperset will
-	// panic.
 	IsSuperset(other Set[T]) bool
 
 	// Each iterates over elements and executes the passed func against each element.
@@ -160,32 +154,52 @@ type Set[T comparable] interface {
 	// use to range over the set.
 	Iterator() *Iterator[T]
 
-	// Remove removes a single element from the set.
-	Remove(i T)
-
-	// RemoveAll removes multiple elements from the set.
-	RemoveAll(i ...T)
-
 	// String provides a convenient string representation
 	// of the current state of the set.
 	String() string
 
 	// SymmetricDifference returns a new set with all elements which are
 	// in either this set or the other set but not in both.
-	//
-	// Note that the argument to SymmetricDifference
-	// must be of the same type as the receiver
-	// of the method. Otherwise, SymmetricDifference
-	// will panic.
 	SymmetricDifference(other Set[T]) Set[T]
 
 	// Union returns a new set with all elements in both sets.
-	//
-	// Note that the argument to Union must be of the
-	// same type as the receiver of the method.
-	// Otherwise, Union will panic.
 	Union(other Set[T]) Set[T]
 
+	// ToSlice returns the members of the set as a slice.
+	ToSlice() []T
+
+	// MarshalJSON will marshal the set into a JSON-based representation.
+	MarshalJSON() ([]byte, error)
+}
+
+// Assert interface: every ReadOnlySet is also a Collection.
+var _ Collection[string] = ReadOnlySet[string](nil)
+
+// Set is the primary interface provided by the mapset package.  It
+// represents an unordered set of data and a large number of
+// operations that can be applied to that set. It extends ReadOnlySet with
+// the methods that modify the set.
+type Set[T comparable] interface {
+	ReadOnlySet[T]
+
+	// Add adds an element to the set. Returns whether
+	// the item was added.
+	Add(val T) bool
+
+	// Append multiple elements to the set. Returns
+	// the number of elements added.
+	Append(val ...T) int
+
+	// Clear removes all elements from the set, leaving
+	// the empty set.
+	Clear()
+
+	// Remove removes a single element from the set.
+	Remove(i T)
+
+	// RemoveAll removes multiple elements from the set.
+	RemoveAll(i ...T)
+
 	// Pop removes and returns an arbitrary item from the set.
 	Pop() (T, bool)
 
@@ -195,17 +209,34 @@ type Set[T comparable] interface {
 	// If n is greater than the set's size, all items are
 	PopN(n int) ([]T, int)
 
-	// ToSlice returns the members of the set as a slice.
-	ToSlice() []T
-
-	// MarshalJSON will marshal the set into a JSON-based representation.
-	MarshalJSON() ([]byte, error)
-
 	// UnmarshalJSON will unmarshal a JSON-based byte slice into a full Set datastructure.
 	// For this to work, set subtypes must implemented the Marshal/Unmarshal interface.
 	UnmarshalJSON(b []byte) error
 }
 
+// Updater is implemented by sets that can be updated in place from another
+// set, which every set of this package can. The UnionUpdate,
+// IntersectUpdate, DifferenceUpdate and SymmetricDifferenceUpdate functions
+// use these methods when the set they update implements them.
+type Updater[T comparable] interface {
+	// UnionUpdate adds all elements of other to this set in place.
+	// Returns the number of elements added.
+	UnionUpdate(other Set[T]) int
+
+	// IntersectUpdate removes all elements of this set that are not
+	// also elements of other. Returns the number of elements removed.
+	IntersectUpdate(other Set[T]) int
+
+	// DifferenceUpdate removes all elements of other from this set.
+	// Returns the number of elements removed.
+	DifferenceUpdate(other Set[T]) int
+
+	// SymmetricDifferenceUpdate updates this set in place to hold the
+	// elements which are in either this set or the other set but not
+	// in both. Returns the number of elements added and removed.
+	SymmetricDifferenceUpdate(other Set[T]) (added, removed int)
+}
+
 // NewSet creates and returns a new set with the given elements.
 // Operations on the resulting set are thread-safe.
 func NewSet[T comparable](vals ...T) Set[T] {
@@ -273,3 +304,28 @@ func Elements[T comparable](s Set[T]) func(func(element T) bool) {
 		})
 	}
 }
+
+// newSetLike returns a new, empty set of element type U that uses the same
+// implementation as s, with room for cardinality elements. Implementations
+// this package cannot construct get the package default, a thread-safe set.
+func newSetLike[U, T comparable](s ReadOnlySet[T], cardinality int) Set[U] {
+	switch v := s.(type) {
+	case *threadUnsafeSet[T]:
+		return newThreadUnsafeSetWithSize[U](cardinality)
+	case *orderedSet[T]:
+		return newOrderedSet[U](cardinality)
+	case *threadSafeOrderedSet[T]:
+		return newThreadSafeOrderedSet[U](cardinality)
+	case emptyCloner[U]:
+		return v.emptyClone()
+	}
+	return newThreadSafeSetWithSize[U](cardinality)
+}
+
+// emptyCloner is implemented by sets whose implementation depends on more
+// than their element type, such as the ordering of a SortedSet, so that
+// newSetLike can still return a set of the same implementation when the
+// element type does not change.
+type emptyCloner[T comparable] interface {
+	emptyClone() Set[T]
+}
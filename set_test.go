@@ -1517,6 +1517,147 @@ func Test_Elements(t *testing.T) {
 	}
 }
 
+func Test_MixedImplementations(t *testing.T) {
+	test := func(t *testing.T, a, b Set[int], sameImpl func(Set[int]) bool) {
+		if !sameImpl(a.Union(b)) || !sameImpl(a.Intersect(b)) ||
+			!sameImpl(a.Difference(b)) || !sameImpl(a.SymmetricDifference(b)) {
+			t.Error("Returned sets should use the same implementation as the receiver")
+		}
+
+		assertEqual(a.Union(b), NewThreadUnsafeSet(1, 2, 3, 4, 5), t)
+		assertEqual(a.Intersect(b), NewThreadUnsafeSet(3), t)
+		assertEqual(a.Difference(b), NewThreadUnsafeSet(1, 2), t)
+		assertEqual(a.SymmetricDifference(b), NewThreadUnsafeSet(1, 2, 4, 5), t)
+
+		if a.Equal(b) {
+			t.Error("Sets with different elements should not be equal")
+		}
+		if !a.ContainsAnyElement(b) {
+			t.Error("Sets sharing 3 should contain any element of each other")
+		}
+		if a.IsSubset(b) || a.IsSuperset(b) || a.IsProperSubset(b) || a.IsProperSuperset(b) {
+			t.Error("Overlapping sets should not be subsets or supersets of each other")
+		}
+
+		sub := a.Intersect(b)
+		if !sub.IsSubset(a) || !sub.IsProperSubset(b) || !a.IsProperSuperset(sub) {
+			t.Error("The intersection should be a proper subset of both sets")
+		}
+	}
+
+	isSafe := func(s Set[int]) bool {
+		_, ok := s.(*threadSafeSet[int])
+		return ok
+	}
+	isUnsafe := func(s Set[int]) bool {
+		_, ok := s.(*threadUnsafeSet[int])
+		return ok
+	}
+
+	t.Run("SafeUnsafe", func(t *testing.T) {
+		test(t, NewSet(1, 2, 3), NewThreadUnsafeSet(3, 4, 5), isSafe)
+	})
+	t.Run("UnsafeSafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet(1, 2, 3), NewSet(3, 4, 5), isUnsafe)
+	})
+}
+
+func Test_UpdateOperations(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		a := ctor(1, 2, 3)
+		if n := UnionUpdate(a, NewThreadUnsafeSet(3, 4, 5)); n != 2 {
+			t.Errorf("UnionUpdate should have added 2 elements, added %d", n)
+		}
+		assertEqual(a, NewSet(1, 2, 3, 4, 5), t)
+
+		if n := IntersectUpdate(a, NewSet(2, 3, 4, 9)); n != 2 {
+			t.Errorf("IntersectUpdate should have removed 2 elements, removed %d", n)
+		}
+		assertEqual(a, NewSet(2, 3, 4), t)
+
+		if n := DifferenceUpdate[int](a, foreignSet[int]{NewSet(4, 9)}); n != 1 {
+			t.Errorf("DifferenceUpdate should have removed 1 element, removed %d", n)
+		}
+		assertEqual(a, NewSet(2, 3), t)
+
+		added, removed := SymmetricDifferenceUpdate(a, NewThreadUnsafeSet(3, 7, 8))
+		if added != 2 || removed != 1 {
+			t.Errorf("SymmetricDifferenceUpdate should have added 2 and removed 1, got %d and %d", added, removed)
+		}
+		assertEqual(a, NewSet(2, 7, 8), t)
+
+		if n := UnionUpdate(a, a); n != 0 {
+			t.Errorf("UnionUpdate with itself should not add anything, added %d", n)
+		}
+		if n := IntersectUpdate(a, a); n != 0 {
+			t.Errorf("IntersectUpdate with itself should not remove anything, removed %d", n)
+		}
+		if added, removed := SymmetricDifferenceUpdate(a.Clone(), a); added != 0 || removed != 3 {
+			t.Errorf("SymmetricDifferenceUpdate with an equal set should remove everything, got %d and %d", added, removed)
+		}
+		if n := DifferenceUpdate(a, a); n != 3 || !a.IsEmpty() {
+			t.Errorf("DifferenceUpdate with itself should empty the set, removed %d", n)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+	t.Run("Foreign", func(t *testing.T) {
+		// Sets that do not implement Updater are updated through their
+		// Set methods.
+		test(t, func(vals ...int) Set[int] {
+			return foreignSet[int]{NewThreadUnsafeSet(vals...)}
+		})
+	})
+}
+
+// Assert interface: the package's mutable sets can be updated in place.
+var (
+	_ Updater[int] = (*threadSafeSet[int])(nil)
+	_ Updater[int] = (*threadUnsafeSet[int])(nil)
+)
+
+// foreignSet stands in for a Set implementation from outside of this
+// package; the built-in sets can only reach it through Collection.
+type foreignSet[T comparable] struct {
+	Set[T]
+}
+
+func Test_ForeignImplementation(t *testing.T) {
+	test := func(t *testing.T, ctor func(vals ...int) Set[int]) {
+		a := ctor(1, 2, 3)
+		b := foreignSet[int]{NewThreadUnsafeSet(3, 4, 5)}
+
+		assertEqual(a.Union(b), NewThreadUnsafeSet(1, 2, 3, 4, 5), t)
+		assertEqual(a.Intersect(b), NewThreadUnsafeSet(3), t)
+		assertEqual(a.Difference(b), NewThreadUnsafeSet(1, 2), t)
+		assertEqual(a.SymmetricDifference(b), NewThreadUnsafeSet(1, 2, 4, 5), t)
+
+		if a.Equal(b) || !a.Equal(foreignSet[int]{NewSet(1, 2, 3)}) {
+			t.Error("Equal should compare elements of a foreign implementation")
+		}
+		if !a.ContainsAnyElement(b) || a.ContainsAnyElement(foreignSet[int]{NewSet(7)}) {
+			t.Error("ContainsAnyElement should look into a foreign implementation")
+		}
+
+		sub := foreignSet[int]{NewSet(1, 2)}
+		if !a.IsSuperset(sub) || !a.IsProperSuperset(sub) || a.IsSubset(sub) || a.IsProperSubset(sub) {
+			t.Error("Set {1, 2, 3} should be a proper superset of a foreign {1, 2}")
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[int])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[int])
+	})
+}
+
 func Test_Example(t *testing.T) {
 	/*
 	   requiredClasses := NewSet()
@@ -0,0 +1,564 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// shardedSet spreads its elements over several shards, each a map with its
+// own lock, so that goroutines adding or looking up different elements
+// rarely wait on each other.
+//
+// Operations on a single element lock only the shard it hashes to. Whole
+// set operations lock every shard, always in the same order; when they
+// involve another thread-safe set, that set's lock is taken before any
+// shard, which is also the order in which the other sets lock a sharded
+// argument, so that mixing implementations cannot deadlock.
+type shardedSet[T comparable] struct {
+	shards []setShard[T]
+	hash   func(T) uint64
+}
+
+type setShard[T comparable] struct {
+	sync.RWMutex
+	uss *threadUnsafeSet[T]
+	// Keep shards on separate cache lines, so that goroutines working on
+	// neighbouring shards don't slow each other down.
+	_ [64 - (unsafe.Sizeof(sync.RWMutex{})+unsafe.Sizeof(uintptr(0)))%64]byte
+}
+
+// Assert concrete types:shardedSet adheres to Set interface and takes part
+// in multi-set locking.
+var (
+	_ Set[string]         = (*shardedSet[string])(nil)
+	_ shardLocked[string] = (*shardedSet[string])(nil)
+)
+
+// NewShardedSet creates and returns a new, empty set whose elements are
+// spread over the given number of shards by the hash function. Operations
+// on the resulting set are thread-safe, and concurrent operations on
+// elements of different shards do not contend on a lock, which makes it
+// suited to write-heavy workloads on many cores.
+//
+// If shards is not positive, one shard per GOMAXPROCS is used. If hash is
+// nil, elements are hashed the way a map hashes its keys: pointers and
+// channels by their address, other values by their contents.
+func NewShardedSet[T comparable](shards int, hash func(T) uint64) Set[T] {
+	if shards <= 0 {
+		shards = runtime.GOMAXPROCS(0)
+	}
+	if hash == nil {
+		hash = hashComparable[T]
+	}
+	return newShardedSet(shards, hash)
+}
+
+func newShardedSet[T comparable](shards int, hash func(T) uint64) *shardedSet[T] {
+	s := &shardedSet[T]{shards: make([]setShard[T], shards), hash: hash}
+	for i := range s.shards {
+		s.shards[i].uss = newThreadUnsafeSet[T]()
+	}
+	return s
+}
+
+func (s *shardedSet[T]) emptyClone() Set[T] {
+	return newShardedSet(len(s.shards), s.hash)
+}
+
+func (s *shardedSet[T]) shardFor(v T) *setShard[T] {
+	return &s.shards[s.hash(v)%uint64(len(s.shards))]
+}
+
+func (s *shardedSet[T]) rlockShards() {
+	for i := range s.shards {
+		s.shards[i].RLock()
+	}
+}
+
+func (s *shardedSet[T]) runlockShards() {
+	for i := len(s.shards) - 1; i >= 0; i-- {
+		s.shards[i].RUnlock()
+	}
+}
+
+func (s *shardedSet[T]) lockShards() {
+	for i := range s.shards {
+		s.shards[i].Lock()
+	}
+}
+
+func (s *shardedSet[T]) unlockShards() {
+	for i := len(s.shards) - 1; i >= 0; i-- {
+		s.shards[i].Unlock()
+	}
+}
+
+// lockWith locks the shards of s, for writing when write is set, and when
+// c is another sharded set, read-locks its shards too. Shards are taken in
+// address order, so that two sharded sets combined with each other from
+// two goroutines cannot deadlock. It returns the collection that should be
+// read in place of c, together with a function releasing every lock taken.
+func (s *shardedSet[T]) lockWith(c Collection[T], write bool) (Collection[T], func()) {
+	lockOwn, unlockOwn := s.rlockShards, s.runlockShards
+	if write {
+		lockOwn, unlockOwn = s.lockShards, s.unlockShards
+	}
+
+	o, ok := c.(*shardedSet[T])
+	switch {
+	case !ok:
+		lockOwn()
+		return c, unlockOwn
+	case o == s:
+		lockOwn()
+		return shardedView[T]{s}, unlockOwn
+	case lessAddr(&o.shards[0].RWMutex, &s.shards[0].RWMutex):
+		o.rlockShards()
+		lockOwn()
+	default:
+		lockOwn()
+		o.rlockShards()
+	}
+	return shardedView[T]{o}, func() {
+		o.runlockShards()
+		unlockOwn()
+	}
+}
+
+func (s *shardedSet[T]) shardMutexes() []*sync.RWMutex {
+	mus := make([]*sync.RWMutex, len(s.shards))
+	for i := range s.shards {
+		mus[i] = &s.shards[i].RWMutex
+	}
+	return mus
+}
+
+func (s *shardedSet[T]) lockedView() Collection[T] {
+	return shardedView[T]{s}
+}
+
+// shardedView reads a sharded set whose shards are already locked.
+type shardedView[T comparable] struct {
+	s *shardedSet[T]
+}
+
+// Assert helper type:shardedView can be read by the set operations.
+var _ Collection[string] = shardedView[string]{}
+
+// isView reports whether c reads s itself.
+func (s *shardedSet[T]) isView(c Collection[T]) bool {
+	v, ok := c.(shardedView[T])
+	return ok && v.s == s
+}
+
+// clearShards empties every shard of s, whose shards must be write-locked.
+func (s *shardedSet[T]) clearShards() {
+	for i := range s.shards {
+		s.shards[i].uss = newThreadUnsafeSet[T]()
+	}
+}
+
+func (v shardedView[T]) Cardinality() int {
+	n := 0
+	for i := range v.s.shards {
+		n += len(*v.s.shards[i].uss)
+	}
+	return n
+}
+
+func (v shardedView[T]) ContainsOne(x T) bool {
+	return v.s.shardFor(x).uss.ContainsOne(x)
+}
+
+func (v shardedView[T]) Each(cb func(T) bool) {
+	for i := range v.s.shards {
+		for elem := range *v.s.shards[i].uss {
+			if cb(elem) {
+				return
+			}
+		}
+	}
+}
+
+// build returns a new set like s holding the elements of the given
+// collections that satisfy keep.
+func (s *shardedSet[T]) build(keep func(T) bool, cs ...Collection[T]) *shardedSet[T] {
+	r := newShardedSet(len(s.shards), s.hash)
+	for _, c := range cs {
+		c.Each(func(elem T) bool {
+			if keep(elem) {
+				r.shardFor(elem).uss.Add(elem)
+			}
+			return false
+		})
+	}
+	return r
+}
+
+func (s *shardedSet[T]) Add(v T) bool {
+	shard := s.shardFor(v)
+	shard.Lock()
+	ret := shard.uss.Add(v)
+	shard.Unlock()
+	return ret
+}
+
+func (s *shardedSet[T]) Append(v ...T) int {
+	added := 0
+	for _, val := range v {
+		if s.Add(val) {
+			added++
+		}
+	}
+	return added
+}
+
+func (s *shardedSet[T]) Cardinality() int {
+	s.rlockShards()
+	defer s.runlockShards()
+	return shardedView[T]{s}.Cardinality()
+}
+
+func (s *shardedSet[T]) Clear() {
+	s.lockShards()
+	s.clearShards()
+	s.unlockShards()
+}
+
+func (s *shardedSet[T]) Clone() Set[T] {
+	s.rlockShards()
+	defer s.runlockShards()
+
+	r := &shardedSet[T]{shards: make([]setShard[T], len(s.shards)), hash: s.hash}
+	for i := range s.shards {
+		r.shards[i].uss = s.shards[i].uss.Clone().(*threadUnsafeSet[T])
+	}
+	return r
+}
+
+func (s *shardedSet[T]) Contains(v ...T) bool {
+	for _, val := range v {
+		if !s.ContainsOne(val) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *shardedSet[T]) ContainsOne(v T) bool {
+	shard := s.shardFor(v)
+	shard.RLock()
+	ret := shard.uss.ContainsOne(v)
+	shard.RUnlock()
+	return ret
+}
+
+func (s *shardedSet[T]) ContainsAny(v ...T) bool {
+	for _, val := range v {
+		if s.ContainsOne(val) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *shardedSet[T]) ContainsAnyElement(other Set[T]) bool {
+	c, unlockOther := lockOther[T](other)
+	defer unlockOther()
+	c, unlock := s.lockWith(c, false)
+	defer unlock()
+
+	return containsAnyOf[T](shardedView[T]{s}, c)
+}
+
+func (s *shardedSet[T]) Difference(other Set[T]) Set[T] {
+	c, unlockOther := lockOther[T](other)
+	defer unlockOther()
+	c, unlock := s.lockWith(c, false)
+	defer unlock()
+
+	return s.build(func(elem T) bool { return !c.ContainsOne(elem) }, shardedView[T]{s})
+}
+
+func (s *shardedSet[T]) Each(cb func(T) bool) {
+	s.rlockShards()
+	defer s.runlockShards()
+	shardedView[T]{s}.Each(cb)
+}
+
+func (s *shardedSet[T]) Equal(other Set[T]) bool {
+	c, unlockOther := lockOther[T](other)
+	defer unlockOther()
+	c, unlock := s.lockWith(c, false)
+	defer unlock()
+
+	return equalCollections[T](shardedView[T]{s}, c)
+}
+
+func (s *shardedSet[T]) Intersect(other Set[T]) Set[T] {
+	c, unlockOther := lockOther[T](other)
+	defer unlockOther()
+	c, unlock := s.lockWith(c, false)
+	defer unlock()
+
+	return s.build(c.ContainsOne, shardedView[T]{s})
+}
+
+func (s *shardedSet[T]) IsEmpty() bool {
+	return s.Cardinality() == 0
+}
+
+func (s *shardedSet[T]) IsProperSubset(other Set[T]) bool {
+	c, unlockOther := lockOther[T](other)
+	defer unlockOther()
+	c, unlock := s.lockWith(c, false)
+	defer unlock()
+
+	v := shardedView[T]{s}
+	return v.Cardinality() < c.Cardinality() && isSubsetOf[T](v, c)
+}
+
+func (s *shardedSet[T]) IsProperSuperset(other Set[T]) bool {
+	c, unlockOther := lockOther[T](other)
+	defer unlockOther()
+	c, unlock := s.lockWith(c, false)
+	defer unlock()
+
+	v := shardedView[T]{s}
+	return v.Cardinality() > c.Cardinality() && isSubsetOf[T](c, v)
+}
+
+func (s *shardedSet[T]) IsSubset(other Set[T]) bool {
+	c, unlockOther := lockOther[T](other)
+	defer unlockOther()
+	c, unlock := s.lockWith(c, false)
+	defer unlock()
+
+	return isSubsetOf[T](shardedView[T]{s}, c)
+}
+
+func (s *shardedSet[T]) IsSuperset(other Set[T]) bool {
+	c, unlockOther := lockOther[T](other)
+	defer unlockOther()
+	c, unlock := s.lockWith(c, false)
+	defer unlock()
+
+	return isSubsetOf[T](c, shardedView[T]{s})
+}
+
+func (s *shardedSet[T]) Iter() <-chan T {
+	return iterCollection[T](s)
+}
+
+func (s *shardedSet[T]) Iterator() *Iterator[T] {
+	return iteratorCollection[T](s)
+}
+
+// Pop removes and returns an arbitrary item, locking one shard at a time.
+func (s *shardedSet[T]) Pop() (v T, ok bool) {
+	for i := range s.shards {
+		shard := &s.shards[i]
+		shard.Lock()
+		v, ok = shard.uss.Pop()
+		shard.Unlock()
+		if ok {
+			return v, true
+		}
+	}
+	return v, false
+}
+
+func (s *shardedSet[T]) PopN(n int) (items []T, count int) {
+	s.lockShards()
+	defer s.unlockShards()
+
+	if n <= 0 {
+		return make([]T, 0), 0
+	}
+	if sn := (shardedView[T]{s}).Cardinality(); n > sn {
+		n = sn
+	}
+	items = make([]T, 0, n)
+	for i := range s.shards {
+		popped, c := s.shards[i].uss.PopN(n - count)
+		items = append(items, popped...)
+		if count += c; count == n {
+			break
+		}
+	}
+	return items, count
+}
+
+func (s *shardedSet[T]) Remove(v T) {
+	shard := s.shardFor(v)
+	shard.Lock()
+	shard.uss.Remove(v)
+	shard.Unlock()
+}
+
+func (s *shardedSet[T]) RemoveAll(i ...T) {
+	for _, elem := range i {
+		s.Remove(elem)
+	}
+}
+
+func (s *shardedSet[T]) String() string {
+	s.rlockShards()
+	defer s.runlockShards()
+	return formatCollection[T](shardedView[T]{s})
+}
+
+func (s *shardedSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	c, unlockOther := lockOther[T](other)
+	defer unlockOther()
+	c, unlock := s.lockWith(c, false)
+	defer unlock()
+
+	v := shardedView[T]{s}
+	r := s.build(func(elem T) bool { return !c.ContainsOne(elem) }, v)
+	c.Each(func(elem T) bool {
+		if !v.ContainsOne(elem) {
+			r.shardFor(elem).uss.Add(elem)
+		}
+		return false
+	})
+	return r
+}
+
+func (s *shardedSet[T]) ToSlice() []T {
+	s.rlockShards()
+	defer s.runlockShards()
+	return collectionToSlice[T](shardedView[T]{s})
+}
+
+func (s *shardedSet[T]) Union(other Set[T]) Set[T] {
+	c, unlockOther := lockOther[T](other)
+	defer unlockOther()
+	c, unlock := s.lockWith(c, false)
+	defer unlock()
+
+	keep := func(T) bool { return true }
+	return s.build(keep, shardedView[T]{s}, c)
+}
+
+func (s *shardedSet[T]) UnionUpdate(other Set[T]) int {
+	c, unlockOther := lockOther[T](other)
+	defer unlockOther()
+	c, unlock := s.lockWith(c, true)
+	defer unlock()
+
+	added := 0
+	c.Each(func(elem T) bool {
+		if s.shardFor(elem).uss.Add(elem) {
+			added++
+		}
+		return false
+	})
+	return added
+}
+
+func (s *shardedSet[T]) IntersectUpdate(other Set[T]) int {
+	c, unlockOther := lockOther[T](other)
+	defer unlockOther()
+	c, unlock := s.lockWith(c, true)
+	defer unlock()
+
+	removed := 0
+	for i := range s.shards {
+		removed += s.shards[i].uss.intersectUpdate(c)
+	}
+	return removed
+}
+
+func (s *shardedSet[T]) DifferenceUpdate(other Set[T]) int {
+	c, unlockOther := lockOther[T](other)
+	defer unlockOther()
+	c, unlock := s.lockWith(c, true)
+	defer unlock()
+
+	if s.isView(c) {
+		removed := c.Cardinality()
+		s.clearShards()
+		return removed
+	}
+	removed := 0
+	c.Each(func(elem T) bool {
+		if uss := s.shardFor(elem).uss; uss.ContainsOne(elem) {
+			uss.Remove(elem)
+			removed++
+		}
+		return false
+	})
+	return removed
+}
+
+func (s *shardedSet[T]) SymmetricDifferenceUpdate(other Set[T]) (added, removed int) {
+	c, unlockOther := lockOther[T](other)
+	defer unlockOther()
+	c, unlock := s.lockWith(c, true)
+	defer unlock()
+
+	if s.isView(c) {
+		removed = c.Cardinality()
+		s.clearShards()
+		return 0, removed
+	}
+	c.Each(func(elem T) bool {
+		uss := s.shardFor(elem).uss
+		if uss.ContainsOne(elem) {
+			uss.Remove(elem)
+			removed++
+		} else {
+			uss.Add(elem)
+			added++
+		}
+		return false
+	})
+	return added, removed
+}
+
+// MarshalJSON creates a JSON array from the set, it marshals all elements
+func (s *shardedSet[T]) MarshalJSON() ([]byte, error) {
+	s.rlockShards()
+	defer s.runlockShards()
+	return marshalCollectionJSON[T](shardedView[T]{s})
+}
+
+// UnmarshalJSON adds the elements of a JSON array to the set.
+func (s *shardedSet[T]) UnmarshalJSON(b []byte) error {
+	var i []T
+	err := json.Unmarshal(b, &i)
+	if err != nil {
+		return err
+	}
+	s.Append(i...)
+
+	return nil
+}
@@ -0,0 +1,227 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Assert interface: a sharded set can be updated in place.
+var _ Updater[int] = (*shardedSet[int])(nil)
+
+func Test_ShardedSetOperations(t *testing.T) {
+	a := NewShardedSet[int](4, nil)
+	a.Append(1, 2, 3, 4, 5)
+	b := NewShardedSet(3, func(v int) uint64 { return uint64(v) })
+	b.Append(4, 5, 6)
+
+	assertEqual(a.Union(b), NewSet(1, 2, 3, 4, 5, 6), t)
+	assertEqual(a.Intersect(b), NewSet(4, 5), t)
+	assertEqual(a.Difference(b), NewSet(1, 2, 3), t)
+	assertEqual(a.SymmetricDifference(NewSet(5, 6)), NewSet(1, 2, 3, 4, 6), t)
+	if _, ok := a.Union(b).(*shardedSet[int]); !ok {
+		t.Error("Results should be sharded sets like the receiver")
+	}
+	if !a.IsProperSuperset(NewThreadUnsafeSet(1, 2)) || a.IsSubset(b) || !a.ContainsAnyElement(b) {
+		t.Error("Subset relations are wrong")
+	}
+	if !NewSet(1, 2, 3, 4, 5).Equal(a) || !a.Equal(a.Clone()) {
+		t.Error("Equal should compare elements across implementations")
+	}
+
+	c := a.Clone()
+	if n := DifferenceUpdate(c, b); n != 2 {
+		t.Errorf("DifferenceUpdate should remove 2 elements, removed %d", n)
+	}
+	if added, removed := SymmetricDifferenceUpdate(c, c); added != 0 || removed != 3 || !c.IsEmpty() {
+		t.Errorf("SymmetricDifferenceUpdate with itself should empty the set, got %d and %d", added, removed)
+	}
+	if n := UnionUpdate(c, b); n != 3 {
+		t.Errorf("UnionUpdate should add 3 elements, added %d", n)
+	}
+	if n := IntersectUpdate(c, a); n != 1 {
+		t.Errorf("IntersectUpdate should remove 1 element, removed %d", n)
+	}
+
+	items, n := a.PopN(10)
+	if n != 5 || !a.IsEmpty() {
+		t.Errorf("PopN should remove every element, removed %d", n)
+	}
+	sort.Ints(items)
+	if len(items) != 5 || items[0] != 1 || items[4] != 5 {
+		t.Errorf("Unexpected popped items: %v", items)
+	}
+	if _, ok := a.Pop(); ok {
+		t.Error("Pop on an empty set should fail")
+	}
+}
+
+func Test_ShardedSetPointers(t *testing.T) {
+	type counter struct{ n int }
+	ptrs := make([]*counter, 100)
+	for i := range ptrs {
+		ptrs[i] = &counter{n: i}
+	}
+	s := NewShardedSet[*counter](8, nil)
+	s.Append(ptrs...)
+
+	for _, p := range ptrs {
+		p.n += 1000
+	}
+	for i, p := range ptrs {
+		if !s.ContainsOne(p) {
+			t.Errorf("Pointer %d should be found after its pointee changed", i)
+		}
+	}
+	if s.Add(ptrs[0]) || s.Cardinality() != 100 {
+		t.Error("Adding a pointer whose pointee changed should not duplicate it")
+	}
+}
+
+func Test_ShardedSetJSON(t *testing.T) {
+	s := NewShardedSet[string](8, nil)
+	s.Append("a", "b")
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+
+	u := NewShardedSet[string](2, nil)
+	if err := json.Unmarshal(b, u); err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+	assertEqual(u, s, t)
+}
+
+func Test_ShardedSetConcurrent(t *testing.T) {
+	sharded := NewShardedSet[int](16, nil)
+	other := NewShardedSet[int](5, nil)
+	safe := NewSet[int]()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				v := g*500 + i
+				sharded.Add(v)
+				other.Add(-v)
+				safe.Add(v)
+				switch i % 6 {
+				case 0:
+					sharded.Union(safe)
+				case 1:
+					safe.Union(sharded)
+				case 2:
+					UnionUpdate(other, sharded)
+				case 3:
+					DifferenceUpdate(other, safe)
+				case 4:
+					IntersectUpdate(safe, sharded)
+				case 5:
+					UnionAll[int](other, safe, sharded)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if sharded.Cardinality() != 4000 {
+		t.Errorf("Expected 4000 elements, got %d", sharded.Cardinality())
+	}
+	if !safe.IsSubset(sharded) {
+		t.Error("The thread-safe set should only hold elements of the sharded set")
+	}
+}
+
+func Test_ShardedSetAllOperationsConcurrent(t *testing.T) {
+	a := NewShardedSet[int](8, nil)
+	b := NewShardedSet[int](8, nil)
+	safe := NewSet[int]()
+	for i := 0; i < 1000; i++ {
+		a.Add(i)
+		b.Add(i + 500)
+		safe.Add(i)
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	run := func(op func(i int)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+					op(i)
+				}
+			}
+		}()
+	}
+	run(func(int) { DifferenceAll[int](a, b) })
+	run(func(int) { DifferenceAll[int](b, a, safe) })
+	run(func(int) { IntersectAll[int](b, safe, a) })
+	for _, s := range []Set[int]{a, b, safe} {
+		s := s
+		run(func(i int) {
+			s.Add(2000 + i%100)
+			s.Remove(2000 + (i+50)%100)
+		})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		time.Sleep(time.Second)
+		close(stop)
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Operations on several sharded sets should not deadlock")
+	}
+}
+
+func Test_ShardedSetSpread(t *testing.T) {
+	s := NewShardedSet[string](4, nil).(*shardedSet[string])
+	for i := 0; i < 400; i++ {
+		s.Add(strconv.Itoa(i))
+	}
+	for i := range s.shards {
+		if n := len(*s.shards[i].uss); n == 0 || n == 400 {
+			t.Errorf("Shard %d holds %d elements; the default hash should spread them", i, n)
+		}
+	}
+}
@@ -36,7 +36,19 @@ import (
 // Sorted returns a sorted slice of a set of any ordered type in ascending order.
 // When sorting floating-point numbers, NaNs are ordered before other values.
 func Sorted[E cmp.Ordered](set Set[E]) []E {
+	if ss, ok := set.(*SortedSet[E]); ok && ss.natural {
+		return ss.ToSlice()
+	}
 	s := set.ToSlice()
 	slices.Sort(s)
 	return s
 }
+
+// SortedFunc returns a slice of the elements of a set of any comparable type
+// sorted in ascending order as determined by the cmp function, with the same
+// contract as for slices.SortFunc.
+func SortedFunc[E comparable](set Set[E], cmp func(a, b E) int) []E {
+	s := set.ToSlice()
+	slices.SortFunc(s, cmp)
+	return s
+}
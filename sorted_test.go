@@ -59,3 +59,24 @@ func Test_Sorted(t *testing.T) {
 		test(t, NewThreadUnsafeSet[string])
 	})
 }
+
+func Test_SortedFunc(t *testing.T) {
+	byLength := func(a, b string) int {
+		return len(a) - len(b)
+	}
+
+	test := func(t *testing.T, ctor func(vals ...string) Set[string]) {
+		sorted := SortedFunc(ctor("banana", "fig", "pear"), byLength)
+
+		if len(sorted) != 3 || sorted[0] != "fig" || sorted[1] != "pear" || sorted[2] != "banana" {
+			t.Errorf("Expected [fig pear banana], got %v", sorted)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet[string])
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet[string])
+	})
+}
@@ -0,0 +1,772 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2023 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"math/rand"
+)
+
+// sortedSetMaxLevel bounds the height of the skip list backing a SortedSet.
+// With a branching factor of 4 it comfortably indexes 2^64 elements.
+const sortedSetMaxLevel = 32
+
+// sortedLink is a forward pointer of a skip list node. span is the number
+// of elements it skips over on the bottom level, which is what allows a
+// SortedSet to find the rank of an element, or the element of a given
+// rank, in logarithmic time.
+type sortedLink[E comparable] struct {
+	node *sortedNode[E]
+	span int
+}
+
+type sortedNode[E comparable] struct {
+	value E
+	prev  *sortedNode[E]
+	next  []sortedLink[E]
+}
+
+// SortedSet is a set of elements kept in ascending order by an indexable
+// skip list. Besides implementing Set, it answers Min, Max, Floor and
+// Ceiling queries, walks ranges of elements in order and converts between
+// elements and their rank, all in logarithmic time.
+//
+// Every method that iterates over the set, including Each, ToSlice,
+// String and MarshalJSON, does so in ascending order, and Pop removes the
+// smallest element. Operations combining two sorted sets sharing the same
+// ordering merge them in linear time. Sets returned by its methods are
+// SortedSets with the same ordering as the receiver.
+//
+// A SortedSet must be created with NewSortedSet or NewSortedSetFunc. The
+// zero value has no ordering: most methods panic when called on it, and
+// UnmarshalJSON returns an error. Operations on a SortedSet are not
+// thread-safe.
+type SortedSet[E comparable] struct {
+	head  sortedNode[E]
+	tail  *sortedNode[E]
+	level int
+	size  int
+	*sortedOrder[E]
+}
+
+// sortedOrder is the ordering of a SortedSet, shared by every set derived
+// from it. Two sets can be merged when they point to the same sortedOrder
+// or when both use the natural ordering of cmp.Compare.
+type sortedOrder[E comparable] struct {
+	compare func(a, b E) int
+	natural bool
+}
+
+// Assert concrete type:SortedSet adheres to Set interface.
+var _ Set[string] = (*SortedSet[string])(nil)
+
+var errSortedSetZero = errors.New("mapset: the zero SortedSet has no ordering")
+
+// NewSortedSet creates and returns a new sorted set with the given
+// elements, ordered by cmp.Compare. When sorting floating-point numbers,
+// NaNs are ordered before other values.
+// Operations on the resulting set are not thread-safe.
+func NewSortedSet[E cmp.Ordered](vals ...E) *SortedSet[E] {
+	s := newSortedSet(&sortedOrder[E]{compare: cmp.Compare[E], natural: true})
+	s.Append(vals...)
+	return s
+}
+
+// NewSortedSetFunc creates and returns a new sorted set with the given
+// elements, ordered by the comparison function cmp. As with
+// slices.SortFunc, cmp(a, b) should return a negative number when a < b, a
+// positive number when a > b and zero when a == b. It must be a strict
+// weak ordering consistent with ==: cmp(a, b) must only return zero when a
+// and b are the same element.
+// Operations on the resulting set are not thread-safe.
+func NewSortedSetFunc[E comparable](cmp func(a, b E) int, vals ...E) *SortedSet[E] {
+	s := newSortedSet(&sortedOrder[E]{compare: cmp})
+	s.Append(vals...)
+	return s
+}
+
+func newSortedSet[E comparable](order *sortedOrder[E]) *SortedSet[E] {
+	s := &SortedSet[E]{level: 1, sortedOrder: order}
+	s.head.next = make([]sortedLink[E], sortedSetMaxLevel)
+	return s
+}
+
+func (s *SortedSet[E]) emptyClone() Set[E] {
+	return newSortedSet(s.sortedOrder)
+}
+
+// sameOrder returns whether other is a SortedSet sharing the ordering of s,
+// in which case both can be merged in a single ordered pass.
+func (s *SortedSet[E]) sameOrder(other Collection[E]) (*SortedSet[E], bool) {
+	o, ok := other.(*SortedSet[E])
+	if !ok || (o.sortedOrder != s.sortedOrder && !(o.natural && s.natural)) {
+		return nil, false
+	}
+	return o, true
+}
+
+func randomSortedLevel() int {
+	level := 1
+	for level < sortedSetMaxLevel && rand.Int63()&3 == 0 {
+		level++
+	}
+	return level
+}
+
+// search returns, for every level, the last node holding a value smaller
+// than v together with its position. The head is at position 0 and the
+// elements at positions 1 to Cardinality.
+func (s *SortedSet[E]) search(v E) (update [sortedSetMaxLevel]*sortedNode[E], rank [sortedSetMaxLevel]int) {
+	if s.sortedOrder == nil {
+		panic(errSortedSetZero)
+	}
+	x := &s.head
+	for i := s.level - 1; i >= 0; i-- {
+		if i < s.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.next[i].node != nil && s.compare(x.next[i].node.value, v) < 0 {
+			rank[i] += x.next[i].span
+			x = x.next[i].node
+		}
+		update[i] = x
+	}
+	return update, rank
+}
+
+// find returns the node holding v, or nil.
+func (s *SortedSet[E]) find(v E) *sortedNode[E] {
+	if s.sortedOrder == nil {
+		panic(errSortedSetZero)
+	}
+	x := &s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i].node != nil && s.compare(x.next[i].node.value, v) < 0 {
+			x = x.next[i].node
+		}
+	}
+	if n := x.next[0].node; n != nil && s.compare(n.value, v) == 0 {
+		return n
+	}
+	return nil
+}
+
+// insert links a new node holding v right after update[0], as found by
+// search.
+func (s *SortedSet[E]) insert(update *[sortedSetMaxLevel]*sortedNode[E], rank *[sortedSetMaxLevel]int, v E) *sortedNode[E] {
+	level := randomSortedLevel()
+	if level > s.level {
+		for i := s.level; i < level; i++ {
+			rank[i] = 0
+			update[i] = &s.head
+			update[i].next[i].span = s.size
+		}
+		s.level = level
+	}
+
+	n := &sortedNode[E]{value: v, next: make([]sortedLink[E], level)}
+	for i := 0; i < level; i++ {
+		n.next[i].node = update[i].next[i].node
+		update[i].next[i].node = n
+		n.next[i].span = update[i].next[i].span - (rank[0] - rank[i])
+		update[i].next[i].span = rank[0] - rank[i] + 1
+	}
+	for i := level; i < s.level; i++ {
+		update[i].next[i].span++
+	}
+
+	if update[0] != &s.head {
+		n.prev = update[0]
+	}
+	if n.next[0].node != nil {
+		n.next[0].node.prev = n
+	} else {
+		s.tail = n
+	}
+	s.size++
+	return n
+}
+
+// delete unlinks x, whose predecessors on every level are in update.
+func (s *SortedSet[E]) delete(update *[sortedSetMaxLevel]*sortedNode[E], x *sortedNode[E]) {
+	for i := 0; i < s.level; i++ {
+		if update[i].next[i].node == x {
+			update[i].next[i].span += x.next[i].span - 1
+			update[i].next[i].node = x.next[i].node
+		} else {
+			update[i].next[i].span--
+		}
+	}
+	if x.next[0].node != nil {
+		x.next[0].node.prev = x.prev
+	} else {
+		s.tail = x.prev
+	}
+	for s.level > 1 && s.head.next[s.level-1].node == nil {
+		s.level--
+	}
+	s.size--
+}
+
+// sortedAppender fills a SortedSet with values given in ascending order in
+// constant time per value, remembering the last node of every level.
+type sortedAppender[E comparable] struct {
+	s      *SortedSet[E]
+	update [sortedSetMaxLevel]*sortedNode[E]
+	rank   [sortedSetMaxLevel]int
+}
+
+func newSortedAppender[E comparable](s *SortedSet[E]) *sortedAppender[E] {
+	a := &sortedAppender[E]{s: s}
+	for i := range a.update {
+		a.update[i] = &s.head
+	}
+	return a
+}
+
+// append adds v, which must be greater than every value already appended.
+func (a *sortedAppender[E]) append(v E) {
+	n := a.s.insert(&a.update, &a.rank, v)
+	for i := range n.next {
+		a.update[i] = n
+		a.rank[i] = a.s.size
+	}
+}
+
+func (s *SortedSet[E]) Add(v E) bool {
+	update, rank := s.search(v)
+	if n := update[0].next[0].node; n != nil && s.compare(n.value, v) == 0 {
+		return false
+	}
+	s.insert(&update, &rank, v)
+	return true
+}
+
+func (s *SortedSet[E]) Append(v ...E) int {
+	prevLen := s.size
+	for _, val := range v {
+		s.Add(val)
+	}
+	return s.size - prevLen
+}
+
+func (s *SortedSet[E]) Cardinality() int {
+	return s.size
+}
+
+func (s *SortedSet[E]) Clear() {
+	for i := range s.head.next {
+		s.head.next[i] = sortedLink[E]{}
+	}
+	s.tail = nil
+	s.level = 1
+	s.size = 0
+}
+
+func (s *SortedSet[E]) Clone() Set[E] {
+	clonedSet := newSortedSet(s.sortedOrder)
+	a := newSortedAppender(clonedSet)
+	for x := s.head.next[0].node; x != nil; x = x.next[0].node {
+		a.append(x.value)
+	}
+	return clonedSet
+}
+
+func (s *SortedSet[E]) Contains(v ...E) bool {
+	for _, val := range v {
+		if s.find(val) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *SortedSet[E]) ContainsOne(v E) bool {
+	return s.find(v) != nil
+}
+
+func (s *SortedSet[E]) ContainsAny(v ...E) bool {
+	for _, val := range v {
+		if s.find(val) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SortedSet[E]) ContainsAnyElement(other Set[E]) bool {
+	c, unlock := lockOther[E](other)
+	defer unlock()
+
+	if o, ok := s.sameOrder(c); ok {
+		found := false
+		s.merge(o, func(v E, inS, inO bool) bool {
+			found = inS && inO
+			return found
+		})
+		return found
+	}
+	return containsAnyOf[E](s, c)
+}
+
+// merge walks s and o together in ascending order, calling fn with every
+// value of either set and whether it belongs to s and to o, until fn
+// returns true.
+func (s *SortedSet[E]) merge(o *SortedSet[E], fn func(v E, inS, inO bool) bool) {
+	x, y := s.head.next[0].node, o.head.next[0].node
+	for x != nil || y != nil {
+		var stop bool
+		switch {
+		case y == nil || (x != nil && s.compare(x.value, y.value) < 0):
+			stop = fn(x.value, true, false)
+			x = x.next[0].node
+		case x == nil || s.compare(x.value, y.value) > 0:
+			stop = fn(y.value, false, true)
+			y = y.next[0].node
+		default:
+			stop = fn(x.value, true, true)
+			x, y = x.next[0].node, y.next[0].node
+		}
+		if stop {
+			return
+		}
+	}
+}
+
+// mergeInto returns a new SortedSet holding the values of the merge of s
+// and o for which keep returns true.
+func (s *SortedSet[E]) mergeInto(o *SortedSet[E], keep func(inS, inO bool) bool) *SortedSet[E] {
+	ret := newSortedSet(s.sortedOrder)
+	a := newSortedAppender(ret)
+	s.merge(o, func(v E, inS, inO bool) bool {
+		if keep(inS, inO) {
+			a.append(v)
+		}
+		return false
+	})
+	return ret
+}
+
+func (s *SortedSet[E]) Difference(other Set[E]) Set[E] {
+	c, unlock := lockOther[E](other)
+	defer unlock()
+
+	if o, ok := s.sameOrder(c); ok {
+		return s.mergeInto(o, func(inS, inO bool) bool { return inS && !inO })
+	}
+	diff := newSortedSet(s.sortedOrder)
+	a := newSortedAppender(diff)
+	for x := s.head.next[0].node; x != nil; x = x.next[0].node {
+		if !c.ContainsOne(x.value) {
+			a.append(x.value)
+		}
+	}
+	return diff
+}
+
+// Each calls cb on every element in ascending order. cb may remove the
+// element it is called with.
+func (s *SortedSet[E]) Each(cb func(E) bool) {
+	for x := s.head.next[0].node; x != nil; {
+		next := x.next[0].node
+		if cb(x.value) {
+			break
+		}
+		x = next
+	}
+}
+
+func (s *SortedSet[E]) Equal(other Set[E]) bool {
+	c, unlock := lockOther[E](other)
+	defer unlock()
+
+	if o, ok := s.sameOrder(c); ok {
+		if s.size != o.size {
+			return false
+		}
+		equal := true
+		s.merge(o, func(v E, inS, inO bool) bool {
+			equal = inS && inO
+			return !equal
+		})
+		return equal
+	}
+	return equalCollections[E](s, c)
+}
+
+func (s *SortedSet[E]) Intersect(other Set[E]) Set[E] {
+	c, unlock := lockOther[E](other)
+	defer unlock()
+
+	if o, ok := s.sameOrder(c); ok {
+		return s.mergeInto(o, func(inS, inO bool) bool { return inS && inO })
+	}
+	intersection := newSortedSet(s.sortedOrder)
+	a := newSortedAppender(intersection)
+	for x := s.head.next[0].node; x != nil; x = x.next[0].node {
+		if c.ContainsOne(x.value) {
+			a.append(x.value)
+		}
+	}
+	return intersection
+}
+
+func (s *SortedSet[E]) IsEmpty() bool {
+	return s.size == 0
+}
+
+func (s *SortedSet[E]) IsProperSubset(other Set[E]) bool {
+	c, unlock := lockOther[E](other)
+	defer unlock()
+
+	return s.size < c.Cardinality() && s.isSubset(c)
+}
+
+func (s *SortedSet[E]) IsProperSuperset(other Set[E]) bool {
+	c, unlock := lockOther[E](other)
+	defer unlock()
+
+	return s.size > c.Cardinality() && isSubsetOf[E](c, s)
+}
+
+func (s *SortedSet[E]) IsSubset(other Set[E]) bool {
+	c, unlock := lockOther[E](other)
+	defer unlock()
+
+	return s.isSubset(c)
+}
+
+// private version of IsSubset for an already locked collection c
+func (s *SortedSet[E]) isSubset(c Collection[E]) bool {
+	if o, ok := s.sameOrder(c); ok {
+		if s.size > o.size {
+			return false
+		}
+		subset := true
+		s.merge(o, func(v E, inS, inO bool) bool {
+			subset = !inS || inO
+			return !subset
+		})
+		return subset
+	}
+	return isSubsetOf[E](s, c)
+}
+
+func (s *SortedSet[E]) IsSuperset(other Set[E]) bool {
+	c, unlock := lockOther[E](other)
+	defer unlock()
+
+	if o, ok := s.sameOrder(c); ok {
+		return o.isSubset(s)
+	}
+	return isSubsetOf[E](c, s)
+}
+
+func (s *SortedSet[E]) Iter() <-chan E {
+	return iterCollection[E](s)
+}
+
+func (s *SortedSet[E]) Iterator() *Iterator[E] {
+	return iteratorCollection[E](s)
+}
+
+// Pop removes and returns the smallest element in case set is not empty,
+// or nil-value of E if set is already empty
+func (s *SortedSet[E]) Pop() (v E, ok bool) {
+	x := s.head.next[0].node
+	if x == nil {
+		return v, false
+	}
+	var update [sortedSetMaxLevel]*sortedNode[E]
+	for i := range update[:s.level] {
+		update[i] = &s.head
+	}
+	s.delete(&update, x)
+	return x.value, true
+}
+
+func (s *SortedSet[E]) PopN(n int) (items []E, count int) {
+	if n <= 0 || s.size == 0 {
+		return make([]E, 0), 0
+	}
+	if n > s.size {
+		n = s.size
+	}
+
+	items = make([]E, 0, n)
+	for count < n {
+		v, _ := s.Pop()
+		items = append(items, v)
+		count++
+	}
+	return items, count
+}
+
+func (s *SortedSet[E]) Remove(v E) {
+	update, _ := s.search(v)
+	if x := update[0].next[0].node; x != nil && s.compare(x.value, v) == 0 {
+		s.delete(&update, x)
+	}
+}
+
+func (s *SortedSet[E]) RemoveAll(i ...E) {
+	for _, elem := range i {
+		s.Remove(elem)
+	}
+}
+
+func (s *SortedSet[E]) String() string {
+	return formatCollection[E](s)
+}
+
+func (s *SortedSet[E]) SymmetricDifference(other Set[E]) Set[E] {
+	c, unlock := lockOther[E](other)
+	defer unlock()
+
+	if o, ok := s.sameOrder(c); ok {
+		return s.mergeInto(o, func(inS, inO bool) bool { return inS != inO })
+	}
+	sd := s.Clone().(*SortedSet[E])
+	sd.symmetricDifferenceUpdate(c)
+	return sd
+}
+
+func (s *SortedSet[E]) ToSlice() []E {
+	keys := make([]E, 0, s.size)
+	for x := s.head.next[0].node; x != nil; x = x.next[0].node {
+		keys = append(keys, x.value)
+	}
+	return keys
+}
+
+func (s *SortedSet[E]) Union(other Set[E]) Set[E] {
+	c, unlock := lockOther[E](other)
+	defer unlock()
+
+	if o, ok := s.sameOrder(c); ok {
+		return s.mergeInto(o, func(inS, inO bool) bool { return true })
+	}
+	unionedSet := s.Clone().(*SortedSet[E])
+	unionedSet.unionUpdate(c)
+	return unionedSet
+}
+
+func (s *SortedSet[E]) UnionUpdate(other Set[E]) int {
+	c, unlock := lockOther[E](other)
+	defer unlock()
+
+	return s.unionUpdate(c)
+}
+
+// private version of UnionUpdate for an already locked collection c
+func (s *SortedSet[E]) unionUpdate(c Collection[E]) int {
+	if c == Collection[E](s) {
+		return 0
+	}
+	prevLen := s.size
+	c.Each(func(elem E) bool {
+		s.Add(elem)
+		return false
+	})
+	return s.size - prevLen
+}
+
+func (s *SortedSet[E]) IntersectUpdate(other Set[E]) int {
+	c, unlock := lockOther[E](other)
+	defer unlock()
+
+	prevLen := s.size
+	s.Each(func(elem E) bool {
+		if !c.ContainsOne(elem) {
+			s.Remove(elem)
+		}
+		return false
+	})
+	return prevLen - s.size
+}
+
+func (s *SortedSet[E]) DifferenceUpdate(other Set[E]) int {
+	c, unlock := lockOther[E](other)
+	defer unlock()
+
+	prevLen := s.size
+	if c == Collection[E](s) {
+		s.Clear()
+		return prevLen
+	}
+	c.Each(func(elem E) bool {
+		s.Remove(elem)
+		return false
+	})
+	return prevLen - s.size
+}
+
+func (s *SortedSet[E]) SymmetricDifferenceUpdate(other Set[E]) (added, removed int) {
+	c, unlock := lockOther[E](other)
+	defer unlock()
+
+	return s.symmetricDifferenceUpdate(c)
+}
+
+// private version of SymmetricDifferenceUpdate for an already locked collection c
+func (s *SortedSet[E]) symmetricDifferenceUpdate(c Collection[E]) (added, removed int) {
+	if c == Collection[E](s) {
+		removed = s.size
+		s.Clear()
+		return added, removed
+	}
+	c.Each(func(elem E) bool {
+		update, rank := s.search(elem)
+		if x := update[0].next[0].node; x != nil && s.compare(x.value, elem) == 0 {
+			s.delete(&update, x)
+			removed++
+		} else {
+			s.insert(&update, &rank, elem)
+			added++
+		}
+		return false
+	})
+	return added, removed
+}
+
+// MarshalJSON creates a JSON array from the set in ascending order, it
+// marshals all elements
+func (s *SortedSet[E]) MarshalJSON() ([]byte, error) {
+	return marshalCollectionJSON[E](s)
+}
+
+// UnmarshalJSON adds the elements of a JSON array to the set. It returns an
+// error if the set is the zero value, which has no ordering.
+func (s *SortedSet[E]) UnmarshalJSON(b []byte) error {
+	if s.sortedOrder == nil {
+		return errSortedSetZero
+	}
+	var i []E
+	err := json.Unmarshal(b, &i)
+	if err != nil {
+		return err
+	}
+	s.Append(i...)
+
+	return nil
+}
+
+// Min returns the smallest element of the set, or false if the set is
+// empty.
+func (s *SortedSet[E]) Min() (v E, ok bool) {
+	if x := s.head.next[0].node; x != nil {
+		return x.value, true
+	}
+	return v, false
+}
+
+// Max returns the largest element of the set, or false if the set is
+// empty.
+func (s *SortedSet[E]) Max() (v E, ok bool) {
+	if s.tail != nil {
+		return s.tail.value, true
+	}
+	return v, false
+}
+
+// Floor returns the largest element of the set less than or equal to v, or
+// false if there is none.
+func (s *SortedSet[E]) Floor(v E) (floor E, ok bool) {
+	x := &s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i].node != nil && s.compare(x.next[i].node.value, v) <= 0 {
+			x = x.next[i].node
+		}
+	}
+	if x == &s.head {
+		return floor, false
+	}
+	return x.value, true
+}
+
+// Ceiling returns the smallest element of the set greater than or equal to
+// v, or false if there is none.
+func (s *SortedSet[E]) Ceiling(v E) (ceiling E, ok bool) {
+	update, _ := s.search(v)
+	if x := update[0].next[0].node; x != nil {
+		return x.value, true
+	}
+	return ceiling, false
+}
+
+// Descend calls cb on every element in descending order, until cb returns
+// true.
+func (s *SortedSet[E]) Descend(cb func(E) bool) {
+	for x := s.tail; x != nil; x = x.prev {
+		if cb(x.value) {
+			break
+		}
+	}
+}
+
+// Range calls cb in ascending order on every element greater than or equal
+// to lo and less than hi, until cb returns true.
+func (s *SortedSet[E]) Range(lo, hi E, cb func(E) bool) {
+	update, _ := s.search(lo)
+	for x := update[0].next[0].node; x != nil && s.compare(x.value, hi) < 0; x = x.next[0].node {
+		if cb(x.value) {
+			break
+		}
+	}
+}
+
+// Rank returns the number of elements of the set less than v. When v is in
+// the set, this is its index in ascending order.
+func (s *SortedSet[E]) Rank(v E) int {
+	_, rank := s.search(v)
+	return rank[0]
+}
+
+// Select returns the element at index i in ascending order, or false if i
+// is out of range.
+func (s *SortedSet[E]) Select(i int) (v E, ok bool) {
+	if i < 0 || i >= s.size {
+		return v, false
+	}
+	// Positions start at 1 for the first element, the head being at 0.
+	target, traversed := i+1, 0
+	x := &s.head
+	for l := s.level - 1; l >= 0; l-- {
+		for x.next[l].node != nil && traversed+x.next[l].span <= target {
+			traversed += x.next[l].span
+			x = x.next[l].node
+		}
+		if traversed == target {
+			return x.value, true
+		}
+	}
+	return v, false
+}
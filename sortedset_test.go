@@ -0,0 +1,303 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"cmp"
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// Assert interface: a SortedSet can be updated in place.
+var _ Updater[int] = (*SortedSet[int])(nil)
+
+func Test_SortedSetOrder(t *testing.T) {
+	s := NewSortedSet(5, 3, 9, 1, 7, 3)
+
+	if got := s.ToSlice(); !reflect.DeepEqual(got, []int{1, 3, 5, 7, 9}) {
+		t.Errorf("ToSlice should be in ascending order, got %v", got)
+	}
+	if got := s.String(); got != "Set{1, 3, 5, 7, 9}" {
+		t.Errorf("String should be in ascending order, got %s", got)
+	}
+	if b, _ := json.Marshal(s); string(b) != "[1,3,5,7,9]" {
+		t.Errorf("MarshalJSON should be in ascending order, got %s", b)
+	}
+	if got := Sorted[int](s); !reflect.DeepEqual(got, []int{1, 3, 5, 7, 9}) {
+		t.Errorf("Sorted should return the elements of a SortedSet as they are, got %v", got)
+	}
+
+	var desc []int
+	s.Descend(func(v int) bool {
+		desc = append(desc, v)
+		return false
+	})
+	if !reflect.DeepEqual(desc, []int{9, 7, 5, 3, 1}) {
+		t.Errorf("Descend should be in descending order, got %v", desc)
+	}
+
+	if v, ok := s.Pop(); !ok || v != 1 {
+		t.Errorf("Pop should remove the smallest element, got %v", v)
+	}
+	if items, n := s.PopN(2); n != 2 || !reflect.DeepEqual(items, []int{3, 5}) {
+		t.Errorf("PopN should remove the smallest elements, got %v", items)
+	}
+}
+
+func Test_SortedSetQueries(t *testing.T) {
+	s := NewSortedSet(10, 20, 30, 40)
+
+	expect := func(name string, got, want int, ok, wantOk bool) {
+		if ok != wantOk || (ok && got != want) {
+			t.Errorf("%s should be (%d, %v), got (%d, %v)", name, want, wantOk, got, ok)
+		}
+	}
+	v, ok := s.Min()
+	expect("Min", v, 10, ok, true)
+	v, ok = s.Max()
+	expect("Max", v, 40, ok, true)
+	v, ok = s.Floor(25)
+	expect("Floor(25)", v, 20, ok, true)
+	v, ok = s.Floor(30)
+	expect("Floor(30)", v, 30, ok, true)
+	v, ok = s.Floor(5)
+	expect("Floor(5)", v, 0, ok, false)
+	v, ok = s.Ceiling(25)
+	expect("Ceiling(25)", v, 30, ok, true)
+	v, ok = s.Ceiling(45)
+	expect("Ceiling(45)", v, 0, ok, false)
+	v, ok = s.Select(2)
+	expect("Select(2)", v, 30, ok, true)
+	v, ok = s.Select(4)
+	expect("Select(4)", v, 0, ok, false)
+
+	if r := s.Rank(30); r != 2 {
+		t.Errorf("Rank(30) should be 2, got %d", r)
+	}
+	if r := s.Rank(35); r != 3 {
+		t.Errorf("Rank(35) should be 3, got %d", r)
+	}
+
+	var inRange []int
+	s.Range(15, 40, func(v int) bool {
+		inRange = append(inRange, v)
+		return false
+	})
+	if !reflect.DeepEqual(inRange, []int{20, 30}) {
+		t.Errorf("Range(15, 40) should be [20 30], got %v", inRange)
+	}
+
+	empty := NewSortedSet[int]()
+	if _, ok := empty.Min(); ok {
+		t.Error("Min of the empty set should not exist")
+	}
+	if _, ok := empty.Max(); ok {
+		t.Error("Max of the empty set should not exist")
+	}
+}
+
+func Test_SortedSetRandomized(t *testing.T) {
+	s := NewSortedSet[int]()
+	model := make(map[int]bool)
+	for i := 0; i < 5000; i++ {
+		v := rand.Intn(1000)
+		if rand.Intn(3) == 0 {
+			s.Remove(v)
+			delete(model, v)
+		} else {
+			s.Add(v)
+			model[v] = true
+		}
+	}
+
+	want := make([]int, 0, len(model))
+	for v := range model {
+		want = append(want, v)
+	}
+	slices.Sort(want)
+
+	if got := s.ToSlice(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("SortedSet holds %v, expected %v", got, want)
+	}
+	for i, v := range want {
+		if r := s.Rank(v); r != i {
+			t.Fatalf("Rank(%d) should be %d, got %d", v, i, r)
+		}
+		if got, ok := s.Select(i); !ok || got != v {
+			t.Fatalf("Select(%d) should be %d, got %d", i, v, got)
+		}
+	}
+	if last, _ := s.Max(); len(want) > 0 && last != want[len(want)-1] {
+		t.Errorf("Max should be %d, got %d", want[len(want)-1], last)
+	}
+}
+
+func Test_SortedSetOperations(t *testing.T) {
+	test := func(t *testing.T, other Set[int]) {
+		a := NewSortedSet(1, 2, 3)
+
+		expect := func(name string, s Set[int], want []int) {
+			ss, ok := s.(*SortedSet[int])
+			if !ok {
+				t.Errorf("%s should return a SortedSet", name)
+				return
+			}
+			if got := ss.ToSlice(); !reflect.DeepEqual(got, want) {
+				t.Errorf("%s should be %v, got %v", name, want, got)
+			}
+		}
+		expect("Union", a.Union(other), []int{1, 2, 3, 4, 5})
+		expect("Intersect", a.Intersect(other), []int{3})
+		expect("Difference", a.Difference(other), []int{1, 2})
+		expect("SymmetricDifference", a.SymmetricDifference(other), []int{1, 2, 4, 5})
+		expect("Clone", a.Clone(), []int{1, 2, 3})
+		expect("Filter", Filter[int](a, func(v int) bool { return v != 2 }), []int{1, 3})
+
+		if a.Equal(other) || !a.ContainsAnyElement(other) || a.IsSubset(other) || a.IsSuperset(other) {
+			t.Error("Overlapping sets should be neither equal, subsets nor supersets")
+		}
+		if !a.Equal(NewSet(3, 2, 1)) || !a.IsProperSuperset(NewThreadUnsafeSet(1, 3)) {
+			t.Error("SortedSet should compare with the other implementations")
+		}
+
+		if added, removed := a.SymmetricDifferenceUpdate(other); added != 2 || removed != 1 {
+			t.Errorf("SymmetricDifferenceUpdate should have added 2 and removed 1, got %d and %d", added, removed)
+		}
+		expect("SymmetricDifferenceUpdate", a, []int{1, 2, 4, 5})
+		if n := a.IntersectUpdate(other); n != 2 {
+			t.Errorf("IntersectUpdate should have removed 2 elements, removed %d", n)
+		}
+		expect("IntersectUpdate", a, []int{4, 5})
+		if n := a.UnionUpdate(other); n != 1 {
+			t.Errorf("UnionUpdate should have added 1 element, added %d", n)
+		}
+		if n := a.DifferenceUpdate(other); n != 3 || !a.IsEmpty() {
+			t.Errorf("DifferenceUpdate should have emptied the set, removed %d", n)
+		}
+	}
+
+	t.Run("Sorted", func(t *testing.T) {
+		test(t, NewSortedSet(3, 4, 5))
+	})
+	t.Run("Safe", func(t *testing.T) {
+		test(t, NewSet(3, 4, 5))
+	})
+	t.Run("Unsafe", func(t *testing.T) {
+		test(t, NewThreadUnsafeSet(3, 4, 5))
+	})
+}
+
+func Test_SortedSetUnmarshalJSON(t *testing.T) {
+	s := NewSortedSet[string]()
+	if err := json.Unmarshal([]byte(`["pear", "apple", "banana"]`), s); err != nil {
+		t.Errorf("Error should be nil: %v", err)
+	}
+	if got := s.ToSlice(); !reflect.DeepEqual(got, []string{"apple", "banana", "pear"}) {
+		t.Errorf("Expected sorted elements, got %v", got)
+	}
+
+	var doc struct {
+		Tags SortedSet[int] `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(`{"tags": [3, 1]}`), &doc); err == nil {
+		t.Error("Unmarshalling into a zero SortedSet should fail")
+	}
+}
+
+func Test_SortedSetZeroValue(t *testing.T) {
+	defer func() {
+		if r := recover(); r != errSortedSetZero {
+			t.Errorf("Adding to a zero SortedSet should panic with %v, got %v", errSortedSetZero, r)
+		}
+	}()
+	var s SortedSet[int]
+	s.Add(1)
+}
+
+func Test_SortedSetFunc(t *testing.T) {
+	type key struct {
+		tenant string
+		id     int
+	}
+	byTenantThenID := func(a, b key) int {
+		if c := cmp.Compare(a.tenant, b.tenant); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.id, b.id)
+	}
+
+	s := NewSortedSetFunc(byTenantThenID, key{"b", 1}, key{"a", 2}, key{"a", 1}, key{"c", 0})
+	want := []key{{"a", 1}, {"a", 2}, {"b", 1}, {"c", 0}}
+	if got := s.ToSlice(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if first, _ := s.Min(); first != want[0] {
+		t.Errorf("Min should be %v, got %v", want[0], first)
+	}
+	if last, _ := s.Max(); last != want[3] {
+		t.Errorf("Max should be %v, got %v", want[3], last)
+	}
+
+	var tenantA []key
+	s.Range(key{"a", 0}, key{"b", 0}, func(k key) bool {
+		tenantA = append(tenantA, k)
+		return false
+	})
+	if !reflect.DeepEqual(tenantA, want[:2]) {
+		t.Errorf("Range over tenant a should be %v, got %v", want[:2], tenantA)
+	}
+
+	union := s.Union(s.Intersect(NewSet(key{"d", 9})))
+	if ss, ok := union.(*SortedSet[key]); !ok || !reflect.DeepEqual(ss.ToSlice(), want) {
+		t.Errorf("Sets derived from a SortedSet should keep its ordering, got %v", union)
+	}
+}
+
+func Test_SortedSetFuncDifferentOrders(t *testing.T) {
+	desc := func(a, b int) int { return cmp.Compare(b, a) }
+	a := NewSortedSetFunc(desc, 1, 2, 3, 4)
+	b := NewSortedSet(3, 4, 5)
+
+	union := a.Union(b).(*SortedSet[int])
+	if got := union.ToSlice(); !reflect.DeepEqual(got, []int{5, 4, 3, 2, 1}) {
+		t.Errorf("Union should follow the ordering of the receiver, got %v", got)
+	}
+	if got := a.Intersect(b).ToSlice(); !reflect.DeepEqual(got, []int{4, 3}) {
+		t.Errorf("Intersect should follow the ordering of the receiver, got %v", got)
+	}
+	if !a.IsSuperset(NewSortedSet(1, 4)) || a.Equal(b) {
+		t.Error("Sets with different orderings should still compare by their elements")
+	}
+	if got := SortedFunc[int](a, cmp.Compare[int]); !reflect.DeepEqual(got, []int{1, 2, 3, 4}) {
+		t.Errorf("SortedFunc should sort by the given function, got %v", got)
+	}
+}
@@ -0,0 +1,257 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SQLFormat selects the column format SQLSet values are written in.
+type SQLFormat int
+
+const (
+	// SQLArray writes sets as PostgreSQL array literals, such as
+	// {a,b,"c d"}, suitable for array columns.
+	SQLArray SQLFormat = iota
+
+	// SQLJSON writes sets as JSON arrays, suitable for JSON or text
+	// columns.
+	SQLJSON
+)
+
+// SQLSet adapts a Set for use with database/sql, as a query argument or as
+// a scan destination, including as a struct field:
+//
+//	var tags mapset.SQLSet[string]
+//	err := db.QueryRow("SELECT tags FROM posts WHERE id = $1", id).Scan(&tags)
+//	...
+//	_, err = db.Exec("UPDATE posts SET tags = $1 WHERE id = $2", tags, id)
+//
+// Scan accepts both the PostgreSQL array literal and the JSON array format,
+// whatever the Format, and replaces the contents of Set, creating a
+// thread-safe set if it is nil. A NULL column scans as an empty set and a
+// nil Set is written as NULL. Elements are converted to and from text as
+// follows: elements implementing encoding.TextMarshaler and
+// encoding.TextUnmarshaler use those, strings are taken as is and numbers
+// and booleans use their Go syntax.
+type SQLSet[T comparable] struct {
+	Set    Set[T]
+	Format SQLFormat
+}
+
+// Assert interfaces: SQLSet is usable with database/sql.
+var (
+	_ sql.Scanner   = (*SQLSet[string])(nil)
+	_ driver.Valuer = SQLSet[string]{}
+)
+
+// Value implements driver.Valuer. Elements are written in a fixed order so
+// that equal sets produce equal values: SQLArray sorts them by their
+// textual form, and SQLJSON as MarshalJSONSorted does.
+func (s SQLSet[T]) Value() (driver.Value, error) {
+	if s.Set == nil {
+		return nil, nil
+	}
+	switch s.Format {
+	case SQLArray:
+		return formatSQLArray[T](s.Set)
+	case SQLJSON:
+		b, err := MarshalJSONSorted[T](s.Set)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	}
+	return nil, fmt.Errorf("mapset: unknown SQL format %d", s.Format)
+}
+
+// Scan implements sql.Scanner.
+func (s *SQLSet[T]) Scan(src any) error {
+	var text string
+	switch v := src.(type) {
+	case nil:
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		return fmt.Errorf("mapset: cannot scan %T into a set", src)
+	}
+
+	var vals []T
+	var err error
+	switch trimmed := strings.TrimSpace(text); {
+	case trimmed == "":
+	case trimmed[0] == '{':
+		vals, err = parseSQLArray[T](trimmed)
+	default:
+		err = json.Unmarshal([]byte(trimmed), &vals)
+	}
+	if err != nil {
+		return err
+	}
+
+	if s.Set == nil {
+		s.Set = newThreadSafeSetWithSize[T](len(vals))
+	} else {
+		s.Set.Clear()
+	}
+	s.Set.Append(vals...)
+	return nil
+}
+
+func formatSQLArray[T comparable](s Set[T]) (string, error) {
+	items, err := formatSortedElements[T](s)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, item := range items {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if !sqlArrayNeedsQuotes(item) {
+			b.WriteString(item)
+			continue
+		}
+		b.WriteByte('"')
+		for j := 0; j < len(item); j++ {
+			if item[j] == '"' || item[j] == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(item[j])
+		}
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String(), nil
+}
+
+// sqlArrayNeedsQuotes reports whether an array element must be quoted to
+// be read back as is.
+func sqlArrayNeedsQuotes(item string) bool {
+	if item == "" || strings.EqualFold(item, "NULL") {
+		return true
+	}
+	return strings.ContainsAny(item, "{}\",\\ \t\n\r\v\f")
+}
+
+var errSQLArrayFormat = errors.New("mapset: malformed array literal")
+
+// parseSQLArray parses a one-dimensional PostgreSQL array literal.
+func parseSQLArray[T comparable](text string) ([]T, error) {
+	if len(text) < 2 || text[len(text)-1] != '}' {
+		return nil, errSQLArrayFormat
+	}
+	body := text[1 : len(text)-1]
+	if strings.TrimSpace(body) == "" {
+		return nil, nil
+	}
+
+	var vals []T
+	for i := 0; ; {
+		for i < len(body) && isSQLArraySpace(body[i]) {
+			i++
+		}
+
+		var item strings.Builder
+		var elem string
+		if i < len(body) && body[i] == '"' {
+			for i++; ; i++ {
+				if i >= len(body) {
+					return nil, errSQLArrayFormat
+				}
+				if body[i] == '\\' {
+					if i++; i >= len(body) {
+						return nil, errSQLArrayFormat
+					}
+				} else if body[i] == '"' {
+					i++
+					break
+				}
+				item.WriteByte(body[i])
+			}
+			for i < len(body) && isSQLArraySpace(body[i]) {
+				i++
+			}
+			elem = item.String()
+		} else {
+			// Unquoted elements end at the next comma, without their
+			// trailing whitespace.
+			end, escaped := 0, false
+			for ; i < len(body) && body[i] != ','; i++ {
+				switch body[i] {
+				case '{', '}', '"':
+					return nil, errSQLArrayFormat
+				case '\\':
+					if i++; i >= len(body) {
+						return nil, errSQLArrayFormat
+					}
+					escaped = true
+					item.WriteByte(body[i])
+					end = item.Len()
+					continue
+				}
+				item.WriteByte(body[i])
+				if !isSQLArraySpace(body[i]) {
+					end = item.Len()
+				}
+			}
+			elem = item.String()[:end]
+			if elem == "" {
+				return nil, errSQLArrayFormat
+			}
+			if !escaped && strings.EqualFold(elem, "NULL") {
+				return nil, errors.New("mapset: a set cannot hold NULL elements")
+			}
+		}
+
+		v, err := parseElement[T](elem)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, v)
+
+		if i == len(body) {
+			return vals, nil
+		}
+		if body[i] != ',' {
+			return nil, errSQLArrayFormat
+		}
+		i++
+	}
+}
+
+func isSQLArraySpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
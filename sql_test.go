@@ -0,0 +1,175 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"database/sql"
+	"net/netip"
+	"testing"
+)
+
+func Test_SQLSetValue(t *testing.T) {
+	v, err := SQLSet[string]{Set: NewSet("b", "a", "c d", `q"\`, "", "null", "{x}")}.Value()
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if want := `{"",a,b,"c d","null","q\"\\","{x}"}`; v != want {
+		t.Errorf("Expected %s, got %v", want, v)
+	}
+
+	v, err = SQLSet[int]{Set: NewThreadUnsafeSet(3, -1, 20), Format: SQLJSON}.Value()
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	var back SQLSet[int]
+	if err := back.Scan(v); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !back.Set.Equal(NewSet(3, -1, 20)) {
+		t.Errorf("Expected {3, -1, 20}, got %v", back.Set)
+	}
+
+	if v, err := (SQLSet[int]{}).Value(); v != nil || err != nil {
+		t.Errorf("A nil set should be written as NULL, got %v, %v", v, err)
+	}
+	if _, err := (SQLSet[int]{Set: NewSet(1), Format: 7}).Value(); err == nil {
+		t.Error("An unknown format should fail")
+	}
+	if _, err := (SQLSet[[2]int]{Set: NewSet([2]int{1, 2})}).Value(); err == nil {
+		t.Error("Elements without a textual form should fail")
+	}
+}
+
+func Test_SQLSetValueDeterministic(t *testing.T) {
+	vals := []int{20, 3, -1, 100, 7, 42, 0, -50}
+	for _, format := range []SQLFormat{SQLArray, SQLJSON} {
+		want, err := SQLSet[int]{Set: NewSet(vals...), Format: format}.Value()
+		if err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		for i := 0; i < 20; i++ {
+			s := NewThreadUnsafeSet[int]()
+			for j := range vals {
+				s.Add(vals[(i+j)%len(vals)])
+			}
+			if v, _ := (SQLSet[int]{Set: s, Format: format}).Value(); v != want {
+				t.Fatalf("Equal sets should produce equal values, got %v and %v", v, want)
+			}
+		}
+	}
+
+	v, _ := SQLSet[int]{Set: NewSet(vals...), Format: SQLJSON}.Value()
+	if want := "[-50,-1,0,3,7,20,42,100]"; v != want {
+		t.Errorf("Expected %s, got %v", want, v)
+	}
+}
+
+func Test_SQLSetScan(t *testing.T) {
+	tests := []struct {
+		src  any
+		want []string
+	}{
+		{`{a,b,"c d"}`, []string{"a", "b", "c d"}},
+		{[]byte(`{ a , "b,c" ,\NULL, "\"x\\" }`), []string{"a", "b,c", "NULL", `"x\`}},
+		{`{"NULL",a b}`, []string{"NULL", "a b"}},
+		{`{}`, nil},
+		{` ["x","y","x"] `, []string{"x", "y"}},
+		{`null`, nil},
+		{nil, nil},
+	}
+	for _, test := range tests {
+		s := SQLSet[string]{Set: NewSet("stale")}
+		if err := s.Scan(test.src); err != nil {
+			t.Errorf("Scan(%v): error should be nil: %v", test.src, err)
+			continue
+		}
+		if !s.Set.Equal(NewSet(test.want...)) {
+			t.Errorf("Scan(%v): expected %v, got %v", test.src, test.want, s.Set)
+		}
+	}
+
+	for _, src := range []any{`{a,NULL}`, `{a`, `{a,,b}`, `{{a},{b}}`, `{"a}`, `[1]`, 42} {
+		var s SQLSet[string]
+		if err := s.Scan(src); err == nil {
+			t.Errorf("Scan(%v) should fail", src)
+		}
+	}
+}
+
+func Test_SQLSetElementTypes(t *testing.T) {
+	var ints SQLSet[int16]
+	if err := ints.Scan(`{1,-2,0x10}`); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !ints.Set.Equal(NewSet[int16](1, -2, 16)) {
+		t.Errorf("Expected {1, -2, 16}, got %v", ints.Set)
+	}
+	if err := ints.Scan(`{1,40000}`); err == nil {
+		t.Error("An element out of range should fail")
+	}
+
+	var bools SQLSet[bool]
+	if err := bools.Scan(`{t,f}`); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !bools.Set.Equal(NewSet(true, false)) {
+		t.Errorf("Expected {true, false}, got %v", bools.Set)
+	}
+
+	addrs := SQLSet[netip.Addr]{Set: NewSet(netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("::1"))}
+	v, err := addrs.Value()
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if v != "{10.0.0.1,::1}" {
+		t.Errorf("Expected {10.0.0.1,::1}, got %v", v)
+	}
+	var back SQLSet[netip.Addr]
+	if err := back.Scan(v); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if !back.Set.Equal(addrs.Set) {
+		t.Errorf("Expected %v, got %v", addrs.Set, back.Set)
+	}
+}
+
+func Test_SQLSetStructField(t *testing.T) {
+	type post struct {
+		ID   int
+		Tags SQLSet[string]
+	}
+	var p post
+	var dest sql.Scanner = &p.Tags
+	if err := dest.Scan([]byte(`{go,sql}`)); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if _, ok := p.Tags.Set.(*threadSafeSet[string]); !ok {
+		t.Error("Scanning into a nil set should create a thread-safe set")
+	}
+	if !p.Tags.Set.Equal(NewSet("go", "sql")) {
+		t.Errorf("Expected {go, sql}, got %v", p.Tags.Set)
+	}
+}
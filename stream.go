@@ -0,0 +1,152 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// jsonDecodeBatch is the number of decoded elements DecodeJSON and
+// DecodeJSONLines add to the set at a time.
+const jsonDecodeBatch = 1024
+
+// EncodeJSON writes s to w as a JSON array, as its MarshalJSON method
+// encodes it, one element at a time instead of building the whole JSON
+// array in memory. The elements are copied out of s first, so a
+// thread-safe set is not locked while w is written to.
+func EncodeJSON[T comparable](w io.Writer, s ReadOnlySet[T]) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteByte('[')
+	first := true
+	err := encodeJSONElements(s.ToSlice(), func(b []byte) error {
+		if !first {
+			bw.WriteByte(',')
+		}
+		first = false
+		_, err := bw.Write(b)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	bw.WriteByte(']')
+	return bw.Flush()
+}
+
+// EncodeJSONLines writes the elements of s to w in the JSON Lines format:
+// each element encoded as JSON on its own line. The elements are copied
+// out of s first, so a thread-safe set is not locked while w is written to.
+func EncodeJSONLines[T comparable](w io.Writer, s ReadOnlySet[T]) error {
+	bw := bufio.NewWriter(w)
+	err := encodeJSONElements(s.ToSlice(), func(b []byte) error {
+		if _, err := bw.Write(b); err != nil {
+			return err
+		}
+		return bw.WriteByte('\n')
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// encodeJSONElements calls write with the JSON encoding of each element of
+// vals, stopping at the first error.
+func encodeJSONElements[T comparable](vals []T, write func([]byte) error) error {
+	for _, elem := range vals {
+		b, err := json.Marshal(elem)
+		if err != nil {
+			return err
+		}
+		if err := write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeJSON reads a JSON array from r and adds its elements to s, decoding
+// one element at a time instead of reading the whole array in memory. A
+// JSON null adds nothing. Elements decoded before an error remain in s.
+// The decoder may buffer data from r beyond the end of the array.
+func DecodeJSON[T comparable](r io.Reader, s Set[T]) error {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("mapset: expected a JSON array, got %v", tok)
+	}
+
+	batch := make([]T, 0, jsonDecodeBatch)
+	for dec.More() {
+		var v T
+		if err := dec.Decode(&v); err != nil {
+			s.Append(batch...)
+			return err
+		}
+		if batch = append(batch, v); len(batch) == cap(batch) {
+			s.Append(batch...)
+			batch = batch[:0]
+		}
+	}
+	s.Append(batch...)
+
+	_, err = dec.Token()
+	return err
+}
+
+// DecodeJSONLines reads JSON values from r until its end, in the JSON Lines
+// format written by EncodeJSONLines, and adds them to s. Elements decoded
+// before an error remain in s.
+func DecodeJSONLines[T comparable](r io.Reader, s Set[T]) error {
+	dec := json.NewDecoder(r)
+	batch := make([]T, 0, jsonDecodeBatch)
+	for {
+		var v T
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			s.Append(batch...)
+			return err
+		}
+		if batch = append(batch, v); len(batch) == cap(batch) {
+			s.Append(batch...)
+			batch = batch[:0]
+		}
+	}
+	s.Append(batch...)
+	return nil
+}
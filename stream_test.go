@@ -0,0 +1,174 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_EncodeDecodeJSON(t *testing.T) {
+	s := NewSet[int]()
+	for i := 0; i < 3*jsonDecodeBatch+5; i++ {
+		s.Add(i * 7)
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeJSON[int](&buf, s); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	var vals []int
+	if err := json.Unmarshal(buf.Bytes(), &vals); err != nil {
+		t.Fatalf("EncodeJSON should write a JSON array: %v", err)
+	}
+
+	u := NewThreadUnsafeSet[int](-1)
+	if err := DecodeJSON[int](&buf, u); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if u.Cardinality() != s.Cardinality()+1 || !u.IsSuperset(s) {
+		t.Error("DecodeJSON should add the elements of the array to the set")
+	}
+}
+
+func Test_EncodeJSONEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeJSON[string](&buf, NewSet[string]()); err != nil || buf.String() != "[]" {
+		t.Errorf("Expected [], got %s, %v", buf.String(), err)
+	}
+	buf.Reset()
+	if err := EncodeJSONLines[string](&buf, NewSet[string]()); err != nil || buf.Len() != 0 {
+		t.Errorf("Expected no output, got %s, %v", buf.String(), err)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func Test_EncodeJSONWriteError(t *testing.T) {
+	s := NewSet[string]()
+	for i := 0; i < 10000; i++ {
+		s.Add(strings.Repeat("x", i%50) + string(rune('a'+i%26)) + string(rune(i)))
+	}
+	if err := EncodeJSON[string](failingWriter{}, s); err == nil {
+		t.Error("A write error should be returned")
+	}
+	if err := EncodeJSONLines[string](failingWriter{}, s); err == nil {
+		t.Error("A write error should be returned")
+	}
+}
+
+// mutatingWriter adds an element to a set from another goroutine on every
+// write, and fails the write if it cannot do so in time.
+type mutatingWriter struct {
+	t *testing.T
+	s Set[int]
+}
+
+func (w mutatingWriter) Write(p []byte) (int, error) {
+	done := make(chan struct{})
+	go func() {
+		w.s.Add(-1)
+		close(done)
+	}()
+	select {
+	case <-done:
+		return len(p), nil
+	case <-time.After(time.Second):
+		w.t.Error("The set should not be locked while the writer runs")
+		return 0, errors.New("set locked")
+	}
+}
+
+func Test_EncodeJSONUnlocked(t *testing.T) {
+	for name, encode := range map[string]func(io.Writer, ReadOnlySet[int]) error{
+		"Array": EncodeJSON[int],
+		"Lines": EncodeJSONLines[int],
+	} {
+		t.Run(name, func(t *testing.T) {
+			test := func(t *testing.T, s Set[int]) {
+				for i := 0; i < 10000; i++ {
+					s.Add(i)
+				}
+				if err := encode(mutatingWriter{t, s}, s); err != nil {
+					t.Fatalf("Error should be nil: %v", err)
+				}
+			}
+			t.Run("Safe", func(t *testing.T) { test(t, NewSet[int]()) })
+			t.Run("Ordered", func(t *testing.T) { test(t, NewOrderedSet[int]()) })
+		})
+	}
+}
+
+func Test_DecodeJSONInputs(t *testing.T) {
+	s := NewSet[string]()
+	if err := DecodeJSON[string](strings.NewReader(` [ "a" , "b", "a" ] `), s); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	assertEqual(s, NewSet("a", "b"), t)
+
+	if err := DecodeJSON[string](strings.NewReader(`null`), s); err != nil {
+		t.Errorf("null should decode as no elements: %v", err)
+	}
+
+	for _, in := range []string{``, `{"a":1}`, `["a",1]`, `["a"`, `"a"`} {
+		if err := DecodeJSON[string](strings.NewReader(in), NewSet[string]()); err == nil {
+			t.Errorf("DecodeJSON(%s) should fail", in)
+		}
+	}
+}
+
+func Test_EncodeDecodeJSONLines(t *testing.T) {
+	s := NewSet("a", "b\nc", `"q"`)
+	var buf bytes.Buffer
+	if err := EncodeJSONLines[string](&buf, s); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"); len(lines) != 3 {
+		t.Errorf("Expected 3 lines, got %q", lines)
+	}
+
+	u := NewSet[string]()
+	if err := DecodeJSONLines[string](&buf, u); err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	assertEqual(u, s, t)
+
+	u = NewSet[string]()
+	err := DecodeJSONLines[string](strings.NewReader("\"a\"\n\"b\"\n42\n"), u)
+	if err == nil {
+		t.Error("An element of the wrong type should fail")
+	}
+	assertEqual(u, NewSet("a", "b"), t)
+}
@@ -0,0 +1,309 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// parseElement parses the textual form of an element. Elements implementing
+// encoding.TextUnmarshaler are parsed by it, otherwise strings are taken as
+// is and numbers and booleans are parsed with the strconv package. Integers
+// accept the base prefixes of Go literals, as the flag package does.
+func parseElement[T comparable](text string) (T, error) {
+	var v T
+	if u, ok := any(&v).(encoding.TextUnmarshaler); ok {
+		err := u.UnmarshalText([]byte(text))
+		return v, err
+	}
+
+	rv := reflect.ValueOf(&v).Elem()
+	var err error
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(text)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		if n, err = strconv.ParseInt(text, 0, rv.Type().Bits()); err == nil {
+			rv.SetInt(n)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var n uint64
+		if n, err = strconv.ParseUint(text, 0, rv.Type().Bits()); err == nil {
+			rv.SetUint(n)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(text, rv.Type().Bits()); err == nil {
+			rv.SetFloat(f)
+		}
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(text); err == nil {
+			rv.SetBool(b)
+		}
+	default:
+		return v, fmt.Errorf("mapset: cannot parse %s elements from text", rv.Type())
+	}
+	if err != nil {
+		return v, fmt.Errorf("mapset: invalid %s element %q", rv.Type(), text)
+	}
+	return v, nil
+}
+
+// formatElement returns the textual form of an element, the reverse of
+// parseElement.
+func formatElement[T comparable](v T) (string, error) {
+	if m, ok := any(v).(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		return string(b), err
+	}
+	if m, ok := any(&v).(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		return string(b), err
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, rv.Type().Bits()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	}
+	return "", fmt.Errorf("mapset: cannot format %T elements as text", v)
+}
+
+// TextFormat describes the textual form of a set used by FormatText,
+// ParseText and FlagValue: its elements, as formatted by
+// encoding.TextMarshaler or their Go syntax, joined by a separator.
+type TextFormat struct {
+	// Separator is written between elements. It defaults to ",".
+	Separator string
+
+	// Quote, if not zero, encloses the elements that are empty or contain
+	// the separator or the quote, which is doubled inside them as in CSV.
+	// Without a quote, such elements cannot be formatted.
+	Quote rune
+
+	// Strict makes parsing fail on duplicate elements instead of dropping
+	// them.
+	Strict bool
+}
+
+// textFormat is the format of the MarshalText and UnmarshalText methods of
+// the sets of this package. It never changes, so that text written by one
+// program can be read by any other.
+var textFormat = TextFormat{Separator: ",", Quote: '"'}
+
+// DefaultTextFormat returns the format of the MarshalText and UnmarshalText
+// methods of the sets of this package: comma-separated elements, double
+// quoted when needed.
+func DefaultTextFormat() TextFormat {
+	return textFormat
+}
+
+func (f TextFormat) separator() string {
+	if f.Separator == "" {
+		return ","
+	}
+	return f.Separator
+}
+
+// FormatText returns the textual form of s in format f. Elements are
+// written in ascending order of their textual form so that equal sets
+// produce equal text.
+func FormatText[T comparable](s ReadOnlySet[T], f TextFormat) (string, error) {
+	return formatText[T](s, f)
+}
+
+// ParseText returns a new thread-safe set holding the elements of text in
+// format f.
+func ParseText[T comparable](text string, f TextFormat) (Set[T], error) {
+	vals, err := parseText[T](text, f)
+	if err != nil {
+		return nil, err
+	}
+	s := newThreadSafeSetWithSize[T](len(vals))
+	s.Append(vals...)
+	return s, nil
+}
+
+// formatSortedElements returns the textual forms of the elements of c in
+// ascending order.
+func formatSortedElements[T comparable](c Collection[T]) ([]string, error) {
+	items, err := formatElements[T](c)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(items)
+	return items, nil
+}
+
+// formatElements returns the textual forms of the elements of c in its
+// iteration order.
+func formatElements[T comparable](c Collection[T]) ([]string, error) {
+	items := make([]string, 0, c.Cardinality())
+	var err error
+	c.Each(func(elem T) bool {
+		var item string
+		if item, err = formatElement(elem); err != nil {
+			return true
+		}
+		items = append(items, item)
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func formatText[T comparable](c Collection[T], f TextFormat) (string, error) {
+	items, err := formatSortedElements[T](c)
+	if err != nil {
+		return "", err
+	}
+	return joinText(items, f)
+}
+
+// formatOrderedText is formatText keeping the iteration order of c.
+func formatOrderedText[T comparable](c Collection[T], f TextFormat) (string, error) {
+	items, err := formatElements[T](c)
+	if err != nil {
+		return "", err
+	}
+	return joinText(items, f)
+}
+
+// joinText joins the textual forms of elements in format f.
+func joinText(items []string, f TextFormat) (string, error) {
+	sep := f.separator()
+	quote := string(f.Quote)
+	var b strings.Builder
+	for i, item := range items {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		if f.Quote == 0 {
+			if strings.Contains(item, sep) || item == "" && len(items) == 1 {
+				return "", fmt.Errorf("mapset: element %q cannot be formatted without quotes", item)
+			}
+			b.WriteString(item)
+			continue
+		}
+		if item != "" && !strings.Contains(item, sep) && !strings.Contains(item, quote) {
+			b.WriteString(item)
+			continue
+		}
+		b.WriteString(quote)
+		b.WriteString(strings.ReplaceAll(item, quote, quote+quote))
+		b.WriteString(quote)
+	}
+	return b.String(), nil
+}
+
+// parseText returns the elements of text in format f, without duplicates.
+func parseText[T comparable](text string, f TextFormat) ([]T, error) {
+	fields, err := splitText(text, f)
+	if err != nil {
+		return nil, err
+	}
+	vals := make([]T, 0, len(fields))
+	seen := make(map[T]struct{}, len(fields))
+	for _, field := range fields {
+		v, err := parseElement[T](field)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[v]; ok {
+			if f.Strict {
+				return nil, fmt.Errorf("mapset: duplicate element %q", field)
+			}
+			continue
+		}
+		seen[v] = struct{}{}
+		vals = append(vals, v)
+	}
+	return vals, nil
+}
+
+// splitText splits text into the textual forms of its elements.
+func splitText(text string, f TextFormat) ([]string, error) {
+	if text == "" {
+		return nil, nil
+	}
+	sep := f.separator()
+	quote := string(f.Quote)
+
+	var fields []string
+	for {
+		if f.Quote != 0 && strings.HasPrefix(text, quote) {
+			var b strings.Builder
+			rest := text[len(quote):]
+			for {
+				i := strings.Index(rest, quote)
+				if i < 0 {
+					return nil, errors.New("mapset: unterminated quoted element")
+				}
+				b.WriteString(rest[:i])
+				rest = rest[i+len(quote):]
+				if !strings.HasPrefix(rest, quote) {
+					break
+				}
+				b.WriteString(quote)
+				rest = rest[len(quote):]
+			}
+			if rest != "" && !strings.HasPrefix(rest, sep) {
+				return nil, errors.New("mapset: unexpected text after quoted element")
+			}
+			fields = append(fields, b.String())
+			text = rest
+		} else if i := strings.Index(text, sep); i >= 0 {
+			fields = append(fields, text[:i])
+			text = text[i:]
+		} else {
+			fields = append(fields, text)
+			text = ""
+		}
+
+		if text == "" {
+			return fields, nil
+		}
+		text = text[len(sep):]
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2013 - 2022 Ralph Caraveo (deckarep@gmail.com)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package mapset
+
+import (
+	"encoding"
+	"net/netip"
+	"testing"
+)
+
+func Test_TextMarshalRoundTrip(t *testing.T) {
+	test := func(t *testing.T, s, u Set[string]) {
+		s.Append("us", "eu", "a,b", `say "hi"`, "")
+		text, err := s.(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		if want := `"","a,b",eu,"say ""hi""",us`; string(text) != want {
+			t.Errorf("Expected %s, got %s", want, text)
+		}
+
+		u.Add("stale")
+		if err := u.(encoding.TextUnmarshaler).UnmarshalText(text); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		assertEqual(u, s, t)
+	}
+
+	t.Run("Safe", func(t *testing.T) { test(t, NewSet[string](), NewSet[string]()) })
+	t.Run("Unsafe", func(t *testing.T) { test(t, NewThreadUnsafeSet[string](), NewThreadUnsafeSet[string]()) })
+}
+
+func Test_TextDefaultFormatFixed(t *testing.T) {
+	f := DefaultTextFormat()
+	f.Separator = ";"
+	if DefaultTextFormat().Separator != "," {
+		t.Error("Changing a returned format should not change the default")
+	}
+	text, _ := NewSet(1, 2).(encoding.TextMarshaler).MarshalText()
+	if string(text) != "1,2" {
+		t.Errorf("Expected 1,2, got %s", text)
+	}
+}
+
+func Test_TextOrdered(t *testing.T) {
+	test := func(t *testing.T, s, u Set[string]) {
+		s.Append("us", "a,b", "eu")
+		text, err := s.(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		if want := `us,"a,b",eu`; string(text) != want {
+			t.Errorf("Expected %s, got %s", want, text)
+		}
+
+		u.Add("stale")
+		if err := u.(encoding.TextUnmarshaler).UnmarshalText(text); err != nil {
+			t.Fatalf("Error should be nil: %v", err)
+		}
+		if got := u.ToSlice(); len(got) != 3 || got[0] != "us" || got[1] != "a,b" || got[2] != "eu" {
+			t.Errorf("Expected [us a,b eu], got %v", got)
+		}
+	}
+
+	t.Run("Safe", func(t *testing.T) { test(t, NewOrderedSet[string](), NewOrderedSet[string]()) })
+	t.Run("Unsafe", func(t *testing.T) { test(t, NewThreadUnsafeOrderedSet[string](), NewThreadUnsafeOrderedSet[string]()) })
+}
+
+func Test_TextEmpty(t *testing.T) {
+	s := NewSet[string]()
+	text, err := FormatText[string](s, DefaultTextFormat())
+	if err != nil || text != "" {
+		t.Errorf("Expected an empty text, got %q, %v", text, err)
+	}
+	if u, err := ParseText[string]("", DefaultTextFormat()); err != nil || !u.IsEmpty() {
+		t.Errorf("Expected an empty set, got %v, %v", u, err)
+	}
+
+	text, _ = FormatText[string](NewSet(""), DefaultTextFormat())
+	if u, _ := ParseText[string](text, DefaultTextFormat()); !u.Equal(NewSet("")) {
+		t.Errorf("Expected a set of the empty string, got %v", u)
+	}
+	if _, err := FormatText[string](NewSet(""), TextFormat{}); err == nil {
+		t.Error("The empty string should not be formatted without quotes")
+	}
+}
+
+func Test_TextFormats(t *testing.T) {
+	f := TextFormat{Separator: " | ", Quote: '\''}
+	s := NewSet("a b", "c | d", "it's")
+	text, err := FormatText[string](s, f)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	if want := `a b | 'c | d' | 'it''s'`; text != want {
+		t.Errorf("Expected %s, got %s", want, text)
+	}
+	u, err := ParseText[string](text, f)
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	assertEqual(u, s, t)
+
+	if _, err := FormatText[string](NewSet("a;b"), TextFormat{Separator: ";"}); err == nil {
+		t.Error("An element containing the separator should not be formatted without quotes")
+	}
+	u, err = ParseText[string](`"a",b"c`, TextFormat{})
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	assertEqual(u, NewSet(`"a"`, `b"c`), t)
+
+	for _, text := range []string{`"a`, `"a"b,c`} {
+		if _, err := ParseText[string](text, DefaultTextFormat()); err == nil {
+			t.Errorf("ParseText(%s) should fail", text)
+		}
+	}
+}
+
+func Test_TextElementTypes(t *testing.T) {
+	ints, err := ParseText[uint8]("1,0x10,255,1", DefaultTextFormat())
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	assertEqual(ints, NewSet[uint8](1, 16, 255), t)
+	if text, _ := FormatText[uint8](ints, DefaultTextFormat()); text != "1,16,255" {
+		t.Errorf("Expected 1,16,255, got %s", text)
+	}
+	if _, err := ParseText[uint8]("1,256", DefaultTextFormat()); err == nil {
+		t.Error("An element out of range should fail")
+	}
+	if _, err := ParseText[int]("1,,2", DefaultTextFormat()); err == nil {
+		t.Error("An empty integer element should fail")
+	}
+
+	addrs, err := ParseText[netip.Addr]("10.0.0.1,::1", DefaultTextFormat())
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	assertEqual(addrs, NewSet(netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("::1")), t)
+
+	if _, err := ParseText[[2]int]("1", DefaultTextFormat()); err == nil {
+		t.Error("Elements without a textual form should fail")
+	}
+}
+
+func Test_TextStrict(t *testing.T) {
+	if _, err := ParseText[string]("a,b,a", TextFormat{Strict: true}); err == nil {
+		t.Error("Duplicates should fail in strict mode")
+	}
+	s, err := ParseText[string]("a,b,a", TextFormat{})
+	if err != nil {
+		t.Fatalf("Error should be nil: %v", err)
+	}
+	assertEqual(s, NewSet("a", "b"), t)
+}
@@ -32,6 +32,9 @@ type threadSafeSet[T comparable] struct {
 	uss *threadUnsafeSet[T]
 }
 
+// Assert concrete type:threadSafeSet takes part in multi-set locking.
+var _ rwLocked[Set[string]] = (*threadSafeSet[string])(nil)
+
 func newThreadSafeSet[T comparable]() *threadSafeSet[T] {
 	return &threadSafeSet[T]{
 		uss: newThreadUnsafeSet[T](),
@@ -44,6 +47,14 @@ func newThreadSafeSetWithSize[T comparable](cardinality int) *threadSafeSet[T] {
 	}
 }
 
+func (t *threadSafeSet[T]) rwMutex() *sync.RWMutex {
+	return &t.RWMutex
+}
+
+func (t *threadSafeSet[T]) unlocked() Set[T] {
+	return t.uss
+}
+
 func (t *threadSafeSet[T]) Add(v T) bool {
 	t.Lock()
 	ret := t.uss.Add(v)
@@ -83,15 +94,10 @@ func (t *threadSafeSet[T]) ContainsAny(v ...T) bool {
 }
 
 func (t *threadSafeSet[T]) ContainsAnyElement(other Set[T]) bool {
-	o := other.(*threadSafeSet[T])
-
-	t.RLock()
-	o.RLock()
-
-	ret := t.uss.ContainsAnyElement(o.uss)
+	o, unlock := rlockPair[Set[T]](t, other)
+	ret := t.uss.ContainsAnyElement(o)
+	unlock()
 
-	t.RUnlock()
-	o.RUnlock()
 	return ret
 }
 
@@ -100,88 +106,104 @@ func (t *threadSafeSet[T]) IsEmpty() bool {
 }
 
 func (t *threadSafeSet[T]) IsSubset(other Set[T]) bool {
-	o := other.(*threadSafeSet[T])
+	o, unlock := rlockPair[Set[T]](t, other)
+	ret := t.uss.IsSubset(o)
+	unlock()
 
-	t.RLock()
-	o.RLock()
-
-	ret := t.uss.IsSubset(o.uss)
-	t.RUnlock()
-	o.RUnlock()
 	return ret
 }
 
 func (t *threadSafeSet[T]) IsProperSubset(other Set[T]) bool {
-	o := other.(*threadSafeSet[T])
+	o, unlock := rlockPair[Set[T]](t, other)
+	defer unlock()
 
-	t.RLock()
-	defer t.RUnlock()
-	o.RLock()
-	defer o.RUnlock()
-
-	return t.uss.IsProperSubset(o.uss)
+	return t.uss.IsProperSubset(o)
 }
 
 func (t *threadSafeSet[T]) IsSuperset(other Set[T]) bool {
-	return other.IsSubset(t)
+	o, unlock := rlockPair[Set[T]](t, other)
+	ret := t.uss.IsSuperset(o)
+	unlock()
+
+	return ret
 }
 
 func (t *threadSafeSet[T]) IsProperSuperset(other Set[T]) bool {
-	return other.IsProperSubset(t)
+	o, unlock := rlockPair[Set[T]](t, other)
+	ret := t.uss.IsProperSuperset(o)
+	unlock()
+
+	return ret
 }
 
 func (t *threadSafeSet[T]) Union(other Set[T]) Set[T] {
-	o := other.(*threadSafeSet[T])
-
-	t.RLock()
-	o.RLock()
-
-	unsafeUnion := t.uss.Union(o.uss).(*threadUnsafeSet[T])
+	o, unlock := rlockPair[Set[T]](t, other)
+	unsafeUnion := t.uss.Union(o).(*threadUnsafeSet[T])
 	ret := &threadSafeSet[T]{uss: unsafeUnion}
-	t.RUnlock()
-	o.RUnlock()
+	unlock()
+
 	return ret
 }
 
 func (t *threadSafeSet[T]) Intersect(other Set[T]) Set[T] {
-	o := other.(*threadSafeSet[T])
-
-	t.RLock()
-	o.RLock()
-
-	unsafeIntersection := t.uss.Intersect(o.uss).(*threadUnsafeSet[T])
+	o, unlock := rlockPair[Set[T]](t, other)
+	unsafeIntersection := t.uss.Intersect(o).(*threadUnsafeSet[T])
 	ret := &threadSafeSet[T]{uss: unsafeIntersection}
-	t.RUnlock()
-	o.RUnlock()
+	unlock()
+
 	return ret
 }
 
 func (t *threadSafeSet[T]) Difference(other Set[T]) Set[T] {
-	o := other.(*threadSafeSet[T])
+	o, unlock := rlockPair[Set[T]](t, other)
+	unsafeDifference := t.uss.Difference(o).(*threadUnsafeSet[T])
+	ret := &threadSafeSet[T]{uss: unsafeDifference}
+	unlock()
 
-	t.RLock()
-	o.RLock()
+	return ret
+}
 
-	unsafeDifference := t.uss.Difference(o.uss).(*threadUnsafeSet[T])
+func (t *threadSafeSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	o, unlock := rlockPair[Set[T]](t, other)
+	unsafeDifference := t.uss.SymmetricDifference(o).(*threadUnsafeSet[T])
 	ret := &threadSafeSet[T]{uss: unsafeDifference}
-	t.RUnlock()
-	o.RUnlock()
+	unlock()
+
 	return ret
 }
 
-func (t *threadSafeSet[T]) SymmetricDifference(other Set[T]) Set[T] {
-	o := other.(*threadSafeSet[T])
+func (t *threadSafeSet[T]) UnionUpdate(other Set[T]) int {
+	c, unlock := lockPairForUpdate[T](t, other)
+	ret := t.uss.unionUpdate(c)
+	unlock()
 
-	t.RLock()
-	o.RLock()
+	return ret
+}
+
+func (t *threadSafeSet[T]) IntersectUpdate(other Set[T]) int {
+	c, unlock := lockPairForUpdate[T](t, other)
+	ret := t.uss.intersectUpdate(c)
+	unlock()
+
+	return ret
+}
+
+func (t *threadSafeSet[T]) DifferenceUpdate(other Set[T]) int {
+	c, unlock := lockPairForUpdate[T](t, other)
+	ret := t.uss.differenceUpdate(c)
+	unlock()
 
-	unsafeDifference := t.uss.SymmetricDifference(o.uss).(*threadUnsafeSet[T])
-	ret := &threadSafeSet[T]{uss: unsafeDifference}
-	t.RUnlock()
-	o.RUnlock()
 	return ret
 }
 
+func (t *threadSafeSet[T]) SymmetricDifferenceUpdate(other Set[T]) (added, removed int) {
+	c, unlock := lockPairForUpdate[T](t, other)
+	added, removed = t.uss.symmetricDifferenceUpdate(c)
+	unlock()
+
+	return added, removed
+}
+
 func (t *threadSafeSet[T]) Clear() {
 	t.Lock()
 	t.uss.Clear()
@@ -252,14 +274,10 @@ func (t *threadSafeSet[T]) Iterator() *Iterator[T] {
 }
 
 func (t *threadSafeSet[T]) Equal(other Set[T]) bool {
-	o := other.(*threadSafeSet[T])
-
-	t.RLock()
-	o.RLock()
+	o, unlock := rlockPair[Set[T]](t, other)
+	ret := t.uss.Equal(o)
+	unlock()
 
-	ret := t.uss.Equal(o.uss)
-	t.RUnlock()
-	o.RUnlock()
 	return ret
 }
 
@@ -311,9 +329,69 @@ func (t *threadSafeSet[T]) MarshalJSON() ([]byte, error) {
 }
 
 func (t *threadSafeSet[T]) UnmarshalJSON(p []byte) error {
-	t.RLock()
+	t.Lock()
+	if t.uss == nil {
+		t.uss = newThreadUnsafeSet[T]()
+	}
 	err := t.uss.UnmarshalJSON(p)
+	t.Unlock()
+
+	return err
+}
+
+func (t *threadSafeSet[T]) GobEncode() ([]byte, error) {
+	t.RLock()
+	b, err := t.uss.GobEncode()
 	t.RUnlock()
 
+	return b, err
+}
+
+func (t *threadSafeSet[T]) GobDecode(p []byte) error {
+	t.Lock()
+	if t.uss == nil {
+		t.uss = newThreadUnsafeSet[T]()
+	}
+	err := t.uss.GobDecode(p)
+	t.Unlock()
+
+	return err
+}
+
+func (t *threadSafeSet[T]) MarshalBinary() ([]byte, error) {
+	t.RLock()
+	b, err := t.uss.MarshalBinary()
+	t.RUnlock()
+
+	return b, err
+}
+
+func (t *threadSafeSet[T]) UnmarshalBinary(p []byte) error {
+	t.Lock()
+	if t.uss == nil {
+		t.uss = newThreadUnsafeSet[T]()
+	}
+	err := t.uss.UnmarshalBinary(p)
+	t.Unlock()
+
+	return err
+}
+
+func (t *threadSafeSet[T]) MarshalText() ([]byte, error) {
+	t.RLock()
+	b, err := t.uss.MarshalText()
+	t.RUnlock()
+
+	return b, err
+}
+
+func (t *threadSafeSet[T]) UnmarshalText(p []byte) error {
+	t.Lock()
+	if t.uss == nil {
+		t.uss = newThreadUnsafeSet[T]()
+	}
+	err := t.uss.UnmarshalText(p)
+	t.Unlock()
+
 	return err
 }
@@ -239,6 +239,133 @@ func Test_ContainsAnyElementConcurrent(t *testing.T) {
 	wg.Wait()
 }
 
+func Test_MixedImplementationsConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s, ss := NewSet[int](), NewThreadUnsafeSet[int]()
+	ints := rand.Perm(N)
+	for _, v := range ints {
+		ss.Add(v)
+	}
+
+	var wg sync.WaitGroup
+	for _, v := range ints {
+		wg.Add(2)
+		go func(v int) {
+			s.Add(v)
+			wg.Done()
+		}(v)
+		go func() {
+			ss.Union(s)
+			ss.Intersect(s)
+			ss.IsSuperset(s)
+			s.Difference(ss)
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	if !s.Equal(ss) || !ss.Equal(s) {
+		t.Errorf("Expected sets to be equal, got: %v", s.SymmetricDifference(ss))
+	}
+}
+
+func Test_CrossOperandsConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s, ss := NewSet[int](), NewSet[int]()
+	ints := rand.Perm(N)
+
+	// Combining two thread-safe sets with each other in both directions,
+	// and a set with itself, while they are written to must not deadlock.
+	var wg sync.WaitGroup
+	for _, v := range ints {
+		wg.Add(3)
+		go func(v int) {
+			s.Add(v)
+			ss.Add(v)
+			wg.Done()
+		}(v)
+		go func() {
+			s.Union(ss)
+			s.IsSubset(ss)
+			s.Equal(s)
+			wg.Done()
+		}()
+		go func() {
+			ss.Intersect(s)
+			ss.Difference(s)
+			ss.SymmetricDifference(ss)
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	if !s.Equal(ss) {
+		t.Errorf("Expected sets to be equal, got: %v", s.SymmetricDifference(ss))
+	}
+}
+
+func Test_UpdateConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s, ss := NewSet[int](), NewSet[int]()
+	ints := rand.Perm(N)
+	for _, v := range ints {
+		s.Add(v)
+		ss.Add(v)
+	}
+
+	// Updating two sets from each other in both directions must not deadlock.
+	var wg sync.WaitGroup
+	for range ints {
+		wg.Add(2)
+		go func() {
+			UnionUpdate(s, ss)
+			IntersectUpdate(s, ss)
+			wg.Done()
+		}()
+		go func() {
+			SymmetricDifferenceUpdate(ss, s)
+			UnionUpdate(ss, s)
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+}
+
+func Test_IntersectAllConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s, ss, sss := NewSet[int](), NewSet[int](), NewSet[int]()
+	ints := rand.Perm(N)
+	for _, v := range ints {
+		s.Add(v)
+		ss.Add(v)
+		sss.Add(v)
+	}
+
+	// The same sets in different orders, mixed with writers, must not deadlock.
+	var wg sync.WaitGroup
+	for _, v := range ints {
+		wg.Add(3)
+		go func() {
+			IntersectAll[int](s, ss, sss)
+			wg.Done()
+		}()
+		go func() {
+			UnionAll[int](sss, s, ss, s)
+			wg.Done()
+		}()
+		go func(v int) {
+			ss.Add(v)
+			DifferenceUpdate(sss, s)
+			wg.Done()
+		}(v)
+	}
+	wg.Wait()
+}
+
 func Test_DifferenceConcurrent(t *testing.T) {
 	runtime.GOMAXPROCS(2)
 
@@ -607,6 +734,33 @@ func Test_UnmarshalJSON(t *testing.T) {
 	}
 }
 
+func Test_UnmarshalJSONConcurrent(t *testing.T) {
+	runtime.GOMAXPROCS(2)
+
+	s := NewSet[int]()
+	ints := rand.Perm(N)
+
+	var wg sync.WaitGroup
+	wg.Add(2 * len(ints))
+	for _, v := range ints {
+		go func(i int) {
+			if err := s.UnmarshalJSON([]byte(fmt.Sprintf("[%d]", i))); err != nil {
+				t.Errorf("Error should be nil: %v", err)
+			}
+			wg.Done()
+		}(v)
+		go func(i int) {
+			s.ContainsOne(i)
+			wg.Done()
+		}(v)
+	}
+	wg.Wait()
+
+	if s.Cardinality() != N {
+		t.Errorf("Expected cardinality %d; got %v", N, s.Cardinality())
+	}
+}
+
 func Test_MarshalJSON(t *testing.T) {
 	expected := NewSet(
 		[]string{
@@ -26,6 +26,8 @@ SOFTWARE.
 package mapset
 
 import (
+	"bytes"
+	"encoding/gob"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -110,10 +112,29 @@ func (s *threadUnsafeSet[T]) ContainsAny(v ...T) bool {
 }
 
 func (s *threadUnsafeSet[T]) ContainsAnyElement(other Set[T]) bool {
-	o := other.(*threadUnsafeSet[T])
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	o, ok := c.(*threadUnsafeSet[T])
+	if !ok {
+		if s.Cardinality() < c.Cardinality() {
+			for elem := range *s {
+				if c.ContainsOne(elem) {
+					return true
+				}
+			}
+			return false
+		}
+		found := false
+		c.Each(func(elem T) bool {
+			found = s.contains(elem)
+			return found
+		})
+		return found
+	}
 
 	// loop over smaller set
-	if s.Cardinality() < other.Cardinality() {
+	if s.Cardinality() < o.Cardinality() {
 		for elem := range *s {
 			if o.contains(elem) {
 				return true
@@ -136,11 +157,20 @@ func (s *threadUnsafeSet[T]) contains(v T) (ok bool) {
 }
 
 func (s *threadUnsafeSet[T]) Difference(other Set[T]) Set[T] {
-	o := other.(*threadUnsafeSet[T])
+	c, unlock := lockOther[T](other)
+	defer unlock()
 
 	diff := newThreadUnsafeSet[T]()
+	if o, ok := c.(*threadUnsafeSet[T]); ok {
+		for elem := range *s {
+			if !o.contains(elem) {
+				diff.add(elem)
+			}
+		}
+		return diff
+	}
 	for elem := range *s {
-		if !o.contains(elem) {
+		if !c.ContainsOne(elem) {
 			diff.add(elem)
 		}
 	}
@@ -156,13 +186,22 @@ func (s *threadUnsafeSet[T]) Each(cb func(T) bool) {
 }
 
 func (s *threadUnsafeSet[T]) Equal(other Set[T]) bool {
-	o := other.(*threadUnsafeSet[T])
+	c, unlock := lockOther[T](other)
+	defer unlock()
 
-	if s.Cardinality() != other.Cardinality() {
+	if s.Cardinality() != c.Cardinality() {
 		return false
 	}
+	if o, ok := c.(*threadUnsafeSet[T]); ok {
+		for elem := range *s {
+			if !o.contains(elem) {
+				return false
+			}
+		}
+		return true
+	}
 	for elem := range *s {
-		if !o.contains(elem) {
+		if !c.ContainsOne(elem) {
 			return false
 		}
 	}
@@ -170,11 +209,31 @@ func (s *threadUnsafeSet[T]) Equal(other Set[T]) bool {
 }
 
 func (s *threadUnsafeSet[T]) Intersect(other Set[T]) Set[T] {
-	o := other.(*threadUnsafeSet[T])
+	c, unlock := lockOther[T](other)
+	defer unlock()
 
 	intersection := newThreadUnsafeSet[T]()
+	o, ok := c.(*threadUnsafeSet[T])
+	if !ok {
+		if s.Cardinality() < c.Cardinality() {
+			for elem := range *s {
+				if c.ContainsOne(elem) {
+					intersection.add(elem)
+				}
+			}
+		} else {
+			c.Each(func(elem T) bool {
+				if s.contains(elem) {
+					intersection.add(elem)
+				}
+				return false
+			})
+		}
+		return intersection
+	}
+
 	// loop over smaller set
-	if s.Cardinality() < other.Cardinality() {
+	if s.Cardinality() < o.Cardinality() {
 		for elem := range *s {
 			if o.contains(elem) {
 				intersection.add(elem)
@@ -195,20 +254,41 @@ func (s *threadUnsafeSet[T]) IsEmpty() bool {
 }
 
 func (s *threadUnsafeSet[T]) IsProperSubset(other Set[T]) bool {
-	return s.Cardinality() < other.Cardinality() && s.IsSubset(other)
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.Cardinality() < c.Cardinality() && s.isSubset(c)
 }
 
 func (s *threadUnsafeSet[T]) IsProperSuperset(other Set[T]) bool {
-	return s.Cardinality() > other.Cardinality() && s.IsSuperset(other)
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.Cardinality() > c.Cardinality() && s.isSuperset(c)
 }
 
 func (s *threadUnsafeSet[T]) IsSubset(other Set[T]) bool {
-	o := other.(*threadUnsafeSet[T])
-	if s.Cardinality() > other.Cardinality() {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.isSubset(c)
+}
+
+// private version of IsSubset for an already locked collection c
+func (s *threadUnsafeSet[T]) isSubset(c Collection[T]) bool {
+	if s.Cardinality() > c.Cardinality() {
 		return false
 	}
+	if o, ok := c.(*threadUnsafeSet[T]); ok {
+		for elem := range *s {
+			if !o.contains(elem) {
+				return false
+			}
+		}
+		return true
+	}
 	for elem := range *s {
-		if !o.contains(elem) {
+		if !c.ContainsOne(elem) {
 			return false
 		}
 	}
@@ -216,7 +296,26 @@ func (s *threadUnsafeSet[T]) IsSubset(other Set[T]) bool {
 }
 
 func (s *threadUnsafeSet[T]) IsSuperset(other Set[T]) bool {
-	return other.IsSubset(s)
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.isSuperset(c)
+}
+
+// private version of IsSuperset for an already locked collection c
+func (s *threadUnsafeSet[T]) isSuperset(c Collection[T]) bool {
+	if s.Cardinality() < c.Cardinality() {
+		return false
+	}
+	if o, ok := c.(*threadUnsafeSet[T]); ok {
+		return o.isSubset(s)
+	}
+	superset := true
+	c.Each(func(elem T) bool {
+		superset = s.contains(elem)
+		return !superset
+	})
+	return superset
 }
 
 func (s *threadUnsafeSet[T]) Iter() <-chan T {
@@ -300,9 +399,26 @@ func (s threadUnsafeSet[T]) String() string {
 }
 
 func (s *threadUnsafeSet[T]) SymmetricDifference(other Set[T]) Set[T] {
-	o := other.(*threadUnsafeSet[T])
+	c, unlock := lockOther[T](other)
+	defer unlock()
 
 	sd := newThreadUnsafeSet[T]()
+	o, ok := c.(*threadUnsafeSet[T])
+	if !ok {
+		for elem := range *s {
+			if !c.ContainsOne(elem) {
+				sd.add(elem)
+			}
+		}
+		c.Each(func(elem T) bool {
+			if !s.contains(elem) {
+				sd.add(elem)
+			}
+			return false
+		})
+		return sd
+	}
+
 	for elem := range *s {
 		if !o.contains(elem) {
 			sd.add(elem)
@@ -326,23 +442,151 @@ func (s threadUnsafeSet[T]) ToSlice() []T {
 }
 
 func (s threadUnsafeSet[T]) Union(other Set[T]) Set[T] {
-	o := other.(*threadUnsafeSet[T])
+	c, unlock := lockOther[T](other)
+	defer unlock()
 
 	n := s.Cardinality()
-	if o.Cardinality() > n {
-		n = o.Cardinality()
+	if c.Cardinality() > n {
+		n = c.Cardinality()
 	}
 	unionedSet := make(threadUnsafeSet[T], n)
 
 	for elem := range s {
 		unionedSet.add(elem)
 	}
-	for elem := range *o {
-		unionedSet.add(elem)
+	if o, ok := c.(*threadUnsafeSet[T]); ok {
+		for elem := range *o {
+			unionedSet.add(elem)
+		}
+		return &unionedSet
 	}
+	c.Each(func(elem T) bool {
+		unionedSet.add(elem)
+		return false
+	})
 	return &unionedSet
 }
 
+func (s *threadUnsafeSet[T]) UnionUpdate(other Set[T]) int {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.unionUpdate(c)
+}
+
+// private version of UnionUpdate for an already locked collection c
+func (s *threadUnsafeSet[T]) unionUpdate(c Collection[T]) int {
+	prevLen := len(*s)
+	if o, ok := c.(*threadUnsafeSet[T]); ok {
+		for elem := range *o {
+			s.add(elem)
+		}
+		return len(*s) - prevLen
+	}
+	c.Each(func(elem T) bool {
+		s.add(elem)
+		return false
+	})
+	return len(*s) - prevLen
+}
+
+func (s *threadUnsafeSet[T]) IntersectUpdate(other Set[T]) int {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.intersectUpdate(c)
+}
+
+// private version of IntersectUpdate for an already locked collection c
+func (s *threadUnsafeSet[T]) intersectUpdate(c Collection[T]) int {
+	prevLen := len(*s)
+	if o, ok := c.(*threadUnsafeSet[T]); ok {
+		for elem := range *s {
+			if !o.contains(elem) {
+				delete(*s, elem)
+			}
+		}
+		return prevLen - len(*s)
+	}
+	for elem := range *s {
+		if !c.ContainsOne(elem) {
+			delete(*s, elem)
+		}
+	}
+	return prevLen - len(*s)
+}
+
+func (s *threadUnsafeSet[T]) DifferenceUpdate(other Set[T]) int {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.differenceUpdate(c)
+}
+
+// private version of DifferenceUpdate for an already locked collection c
+func (s *threadUnsafeSet[T]) differenceUpdate(c Collection[T]) int {
+	prevLen := len(*s)
+	o, ok := c.(*threadUnsafeSet[T])
+	switch {
+	case ok && o == s:
+		s.Clear()
+	case ok:
+		// loop over smaller set
+		if len(*s) < len(*o) {
+			for elem := range *s {
+				if o.contains(elem) {
+					delete(*s, elem)
+				}
+			}
+		} else {
+			for elem := range *o {
+				delete(*s, elem)
+			}
+		}
+	default:
+		c.Each(func(elem T) bool {
+			delete(*s, elem)
+			return false
+		})
+	}
+	return prevLen - len(*s)
+}
+
+func (s *threadUnsafeSet[T]) SymmetricDifferenceUpdate(other Set[T]) (added, removed int) {
+	c, unlock := lockOther[T](other)
+	defer unlock()
+
+	return s.symmetricDifferenceUpdate(c)
+}
+
+// private version of SymmetricDifferenceUpdate for an already locked collection c
+func (s *threadUnsafeSet[T]) symmetricDifferenceUpdate(c Collection[T]) (added, removed int) {
+	toggle := func(elem T) bool {
+		if s.contains(elem) {
+			delete(*s, elem)
+			removed++
+		} else {
+			s.add(elem)
+			added++
+		}
+		return false
+	}
+
+	o, ok := c.(*threadUnsafeSet[T])
+	switch {
+	case ok && o == s:
+		removed = len(*s)
+		s.Clear()
+	case ok:
+		for elem := range *o {
+			toggle(elem)
+		}
+	default:
+		c.Each(toggle)
+	}
+	return added, removed
+}
+
 // MarshalJSON creates a JSON array from the set, it marshals all elements
 func (s threadUnsafeSet[T]) MarshalJSON() ([]byte, error) {
 	items := make([]string, 0, s.Cardinality())
@@ -371,3 +615,63 @@ func (s *threadUnsafeSet[T]) UnmarshalJSON(b []byte) error {
 
 	return nil
 }
+
+// GobEncode encodes the elements of the set as a gob slice.
+func (s threadUnsafeSet[T]) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s.ToSlice()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode replaces the contents of the set with the elements encoded
+// by GobEncode.
+func (s *threadUnsafeSet[T]) GobDecode(b []byte) error {
+	var i []T
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&i); err != nil {
+		return err
+	}
+	*s = make(threadUnsafeSet[T], len(i))
+	s.Append(i...)
+
+	return nil
+}
+
+// MarshalBinary encodes the set in a compact binary form. Only sets of
+// integers and strings can be encoded.
+func (s threadUnsafeSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalCollectionBinary[T](&s, false)
+}
+
+// UnmarshalBinary replaces the contents of the set with the elements
+// encoded by MarshalBinary.
+func (s *threadUnsafeSet[T]) UnmarshalBinary(b []byte) error {
+	i, err := unmarshalBinaryElements[T](b)
+	if err != nil {
+		return err
+	}
+	*s = make(threadUnsafeSet[T], len(i))
+	s.Append(i...)
+
+	return nil
+}
+
+// MarshalText encodes the set in the format returned by DefaultTextFormat.
+func (s threadUnsafeSet[T]) MarshalText() ([]byte, error) {
+	text, err := formatText[T](&s, textFormat)
+	return []byte(text), err
+}
+
+// UnmarshalText replaces the contents of the set with the elements of
+// text in the format returned by DefaultTextFormat.
+func (s *threadUnsafeSet[T]) UnmarshalText(text []byte) error {
+	i, err := parseText[T](string(text), textFormat)
+	if err != nil {
+		return err
+	}
+	*s = make(threadUnsafeSet[T], len(i))
+	s.Append(i...)
+
+	return nil
+}